@@ -1,5 +1,3 @@
-// +build jwkohnen_airac_proto
-
 /*
  * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
  *
@@ -18,23 +16,89 @@
 
 package airac
 
-//go:generate protoc --go_out=./ proto/airac.proto
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/airac.proto proto/service/service.proto
 
 import (
-	"math"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/jwkohnen/airac/proto"
 )
 
-// FromProto converts an AIRAC protobuffer message to an AIRAC value.
-func FromProto(a proto.AiracMessage) AIRAC {
-	if a.Airac19010110 > math.MaxUint16 {
-		return 0
+// lastProto is the last cycle FromProto accepts, the last one of year 2192,
+// the upper limit of FromDate.
+var lastProto = FromDate(time.Date(2192, time.December, 31, 0, 0, 0, 0, time.UTC)) // nolint:gochecknoglobals
+
+// FromProto converts an AIRAC protobuffer message to an AIRAC value. The
+// cycle number airac19010110 is authoritative. It returns an error if the
+// cycle number is beyond year 2192, or if any of the derived fields that are
+// set disagree with it.
+func FromProto(m *proto.AiracMessage) (AIRAC, error) {
+	if m == nil {
+		return 0, errors.New("illegal AIRAC message: nil")
+	}
+
+	if m.GetAirac19010110() > uint32(lastProto) {
+		return 0, fmt.Errorf("illegal AIRAC message: cycle number %d out of range", m.GetAirac19010110())
+	}
+	a := AIRAC(m.GetAirac19010110())
+
+	if ident := m.GetIdent(); ident != "" && ident != a.String() {
+		return 0, fmt.Errorf("illegal AIRAC message: ident %q does not match cycle %s", ident, a)
+	}
+	if year := m.GetYear(); year != 0 && int(year) != a.Year() {
+		return 0, fmt.Errorf("illegal AIRAC message: year %d does not match cycle %s", year, a)
+	}
+	if ordinal := m.GetOrdinal(); ordinal != 0 && int(ordinal) != a.Ordinal() {
+		return 0, fmt.Errorf("illegal AIRAC message: ordinal %d does not match cycle %s", ordinal, a)
 	}
-	return AIRAC(a.Airac19010110)
+	if eff := m.GetEffective(); eff != nil && !eff.AsTime().Equal(a.Effective()) {
+		return 0, fmt.Errorf("illegal AIRAC message: effective %s does not match cycle %s", eff.AsTime(), a)
+	}
+	if exp := m.GetExpires(); exp != nil && !exp.AsTime().Equal((a + 1).Effective()) {
+		return 0, fmt.Errorf("illegal AIRAC message: expires %s does not match cycle %s", exp.AsTime(), a)
+	}
+
+	return a, nil
+}
+
+// Proto converts an AIRAC value to an AIRAC protobuffer message with all fields
+// set.
+func (a AIRAC) Proto() *proto.AiracMessage {
+	return &proto.AiracMessage{
+		Airac19010110: uint32(a),
+		Ident:         a.String(),
+		Year:          int32(a.Year()),
+		Ordinal:       int32(a.Ordinal()),
+		Effective:     timestamppb.New(a.Effective()),
+		Expires:       timestamppb.New((a + 1).Effective()),
+	}
+}
+
+// RangeFromProto converts an AIRAC range protobuffer message to a Range. Both
+// cycles are validated like by FromProto, and last must not be before first.
+func RangeFromProto(m *proto.AiracRange) (Range, error) {
+	first, err := FromProto(m.GetFirst())
+	if err != nil {
+		return Range{}, fmt.Errorf("first: %w", err)
+	}
+
+	last, err := FromProto(m.GetLast())
+	if err != nil {
+		return Range{}, fmt.Errorf("last: %w", err)
+	}
+
+	if last < first {
+		return Range{}, fmt.Errorf("illegal AIRAC range: last %s is before first %s", last, first)
+	}
+
+	return Range{First: first, Last: last}, nil
 }
 
-// Proto converts an AIRAC value to an AIRAC protobuffer message.
-func (a AIRAC) Proto() proto.AiracMessage {
-	return proto.AiracMessage{Airac19010110: uint32(a)}
+// Proto converts a Range to an AIRAC range protobuffer message.
+func (r Range) Proto() *proto.AiracRange {
+	return &proto.AiracRange{First: r.First.Proto(), Last: r.Last.Proto()}
 }
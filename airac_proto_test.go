@@ -1,5 +1,3 @@
-// +build jwkohnen_airac_proto
-
 /*
  * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
  *
@@ -21,6 +19,9 @@ package airac
 import (
 	"math"
 	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/jwkohnen/airac/proto"
 )
@@ -28,7 +29,10 @@ import (
 func TestProto(t *testing.T) {
 	for want := AIRAC(0); want < FromStringMust("9213"); want++ {
 		p := want.Proto()
-		got := FromProto(p)
+		got, err := FromProto(p)
+		if err != nil {
+			t.Fatalf("%s: %v", want, err)
+		}
 		if want != got {
 			t.Errorf("Want %v, got %x", want, got)
 		}
@@ -36,10 +40,82 @@ func TestProto(t *testing.T) {
 }
 
 func TestProtoOverflow(t *testing.T) {
-	want := AIRAC(0)
-	p := proto.AiracMessage{Airac19010110: math.MaxUint16 + 1}
-	got := FromProto(p)
+	p := &proto.AiracMessage{Airac19010110: math.MaxUint16 + 1}
+	if got, err := FromProto(p); err == nil {
+		t.Errorf("Want error, got %s", got)
+	}
+}
+
+func TestProtoAfter2192(t *testing.T) {
+	last := FromDate(time.Date(2192, time.December, 31, 0, 0, 0, 0, time.UTC))
+	if got, err := FromProto(last.Proto()); err != nil || got != last {
+		t.Errorf("Want %s, got %s, %v", last, got, err)
+	}
+
+	for _, n := range []uint32{uint32(last) + 1, 60000} {
+		if got, err := FromProto(&proto.AiracMessage{Airac19010110: n}); err == nil {
+			t.Errorf("%d: want error, got %s effective %s", n, got, got.Effective().Format("2006-01-02"))
+		}
+	}
+}
+
+func TestProtoNumberOnly(t *testing.T) {
+	want := FromStringMust("2101")
+	got, err := FromProto(&proto.AiracMessage{Airac19010110: uint32(want)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Want %s, got %s", want, got)
+	}
+}
+
+func TestProtoInconsistent(t *testing.T) {
+	a := FromStringMust("2101")
+
+	testt := []struct {
+		name   string
+		modify func(m *proto.AiracMessage)
+	}{
+		{"ident", func(m *proto.AiracMessage) { m.Ident = "2102" }},
+		{"year", func(m *proto.AiracMessage) { m.Year = 2020 }},
+		{"ordinal", func(m *proto.AiracMessage) { m.Ordinal = 2 }},
+		{"effective", func(m *proto.AiracMessage) { m.Effective = timestamppb.New(a.Effective().Add(time.Minute)) }},
+		{"expires", func(m *proto.AiracMessage) { m.Expires = timestamppb.New(a.Effective()) }},
+	}
+
+	for _, tt := range testt {
+		m := a.Proto()
+		tt.modify(m)
+		if got, err := FromProto(m); err == nil {
+			t.Errorf("%s: want error, got %s", tt.name, got)
+		}
+	}
+
+	if _, err := FromProto(nil); err == nil {
+		t.Error("nil message: want error")
+	}
+}
+
+func TestRangeProto(t *testing.T) {
+	want, err := ParseRange("2013..2102")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := RangeFromProto(want.Proto())
+	if err != nil {
+		t.Fatal(err)
+	}
 	if got != want {
 		t.Errorf("Want %s, got %s", want, got)
 	}
+
+	if _, err := RangeFromProto(&proto.AiracRange{First: want.First.Proto()}); err == nil {
+		t.Error("range without last: want error")
+	}
+
+	if _, err := RangeFromProto(Range{First: want.Last, Last: want.First}.Proto()); err == nil {
+		t.Error("inverted range: want error")
+	}
 }
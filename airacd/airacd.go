@@ -0,0 +1,303 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package airacd serves AIRAC cycle calculations as a JSON web service. The
+// Server is an http.Handler that can be embedded in any HTTP server.
+//
+// Endpoints:
+//
+//	GET /v1/current                   the cycle effective now
+//	GET /v1/cycles/{ident}            a cycle by identifier, e.g. 2101
+//	GET /v1/dates/{date}              the cycle effective at a date (YYYY-MM-DD or RFC 3339)
+//	GET /v1/years/{year}              all cycles of a year
+//	GET /v1/ranges?from={ident}&to={ident}  a range of cycles
+//	GET /v1/calendar.ics?year={year}  an iCalendar file of a year, or of a
+//	                                  range with from and to; optional
+//	                                  milestones=true and alarm=7d (repeatable)
+//	GET /v1/openapi.json              the OpenAPI document of this service
+//
+// Cycles are encoded in the structured form of airac.Info. Errors are always
+// encoded as JSON.
+package airacd
+
+import (
+	"bytes"
+	_ "embed" // for the OpenAPI document
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/ics"
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+const dateFormat = "2006-01-02"
+
+// Options configures a Server.
+type Options struct {
+	// Clock is the time source of /v1/current. If nil, airac.SystemClock is
+	// used.
+	Clock airac.Clock
+}
+
+// Server is an http.Handler serving the endpoints of the package
+// documentation.
+type Server struct {
+	clock airac.Clock
+	mux   *http.ServeMux
+}
+
+// CycleList is the response of endpoints that return several cycles.
+type CycleList struct {
+	Cycles []airac.Info `json:"cycles"`
+}
+
+// Error is the response of failed requests.
+type Error struct {
+	Error string `json:"error"`
+}
+
+// errNotFound is an error that maps to 404 Not Found; other errors of
+// handlers map to 400 Bad Request.
+var errNotFound = errors.New("not found")
+
+// New returns a Server.
+func New(opts Options) *Server {
+	s := &Server{clock: opts.Clock, mux: http.NewServeMux()}
+	if s.clock == nil {
+		s.clock = airac.SystemClock{}
+	}
+
+	s.mux.HandleFunc("/v1/current", s.get(s.current))
+	s.mux.HandleFunc("/v1/cycles/", s.get(s.cycle))
+	s.mux.HandleFunc("/v1/dates/", s.get(s.date))
+	s.mux.HandleFunc("/v1/years/", s.get(s.year))
+	s.mux.HandleFunc("/v1/ranges", s.get(s.ranges))
+	s.mux.HandleFunc("/v1/calendar.ics", s.calendar)
+	s.mux.HandleFunc("/v1/openapi.json", s.openAPI)
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, Error{Error: "no such endpoint"})
+	})
+
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// get adapts a handler function that returns a response value to an
+// http.HandlerFunc that only accepts GET and HEAD requests.
+func (s *Server) get(h func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowGet(w, r) {
+			return
+		}
+
+		v, err := h(r)
+		switch {
+		case errors.Is(err, errNotFound):
+			writeJSON(w, http.StatusNotFound, Error{Error: err.Error()})
+		case err != nil:
+			writeJSON(w, http.StatusBadRequest, Error{Error: err.Error()})
+		default:
+			writeJSON(w, http.StatusOK, v)
+		}
+	}
+}
+
+func (s *Server) current(*http.Request) (interface{}, error) {
+	return airac.FromDate(s.clock.Now()).Info(), nil
+}
+
+func (s *Server) cycle(r *http.Request) (interface{}, error) {
+	ident, err := pathParam(r, "/v1/cycles/")
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := airac.FromString(ident)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errNotFound, err)
+	}
+
+	return a.Info(), nil
+}
+
+func (s *Server) date(r *http.Request) (interface{}, error) {
+	param, err := pathParam(r, "/v1/dates/")
+	if err != nil {
+		return nil, err
+	}
+
+	layout := time.RFC3339
+	if len(param) == len(dateFormat) {
+		layout = dateFormat
+	}
+
+	t, err := time.Parse(layout, param)
+	if err != nil {
+		return nil, fmt.Errorf("illegal date %q, want YYYY-MM-DD or RFC 3339", param)
+	}
+
+	return airac.FromDate(t).Info(), nil
+}
+
+func (s *Server) year(r *http.Request) (interface{}, error) {
+	param, err := pathParam(r, "/v1/years/")
+	if err != nil {
+		return nil, err
+	}
+
+	year, err := strconv.Atoi(param)
+	if err != nil || year < 1902 || year > 2192 {
+		return nil, fmt.Errorf("illegal year %q, want 1902 to 2192", param)
+	}
+
+	return list(airac.CyclesInYear(year)), nil
+}
+
+func (s *Server) ranges(r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	from, to := q.Get("from"), q.Get("to")
+	if from == "" || to == "" {
+		return nil, errors.New("query parameters from and to are required")
+	}
+
+	rng, err := airac.ParseRange(from + ".." + to)
+	if err != nil {
+		return nil, err
+	}
+
+	return list(rng), nil
+}
+
+// calendar writes an iCalendar file rather than JSON, so it does not use get.
+func (s *Server) calendar(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	rng, opts, err := calendarQuery(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, Error{Error: err.Error()})
+		return
+	}
+	opts.Stamp = s.clock.Now()
+
+	var buf bytes.Buffer
+	if err := ics.Write(&buf, rng, opts); err != nil {
+		writeJSON(w, http.StatusInternalServerError, Error{Error: err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="airac.ics"`)
+	_, _ = w.Write(buf.Bytes())
+}
+
+func calendarQuery(r *http.Request) (airac.Range, ics.Options, error) {
+	var opts ics.Options
+	q := r.URL.Query()
+
+	var rng airac.Range
+	switch year, from, to := q.Get("year"), q.Get("from"), q.Get("to"); {
+	case year != "" && from == "" && to == "":
+		y, err := strconv.Atoi(year)
+		if err != nil || y < 1902 || y > 2192 {
+			return rng, opts, fmt.Errorf("illegal year %q, want 1902 to 2192", year)
+		}
+		rng = airac.CyclesInYear(y)
+	case year == "" && from != "" && to != "":
+		var err error
+		if rng, err = airac.ParseRange(from + ".." + to); err != nil {
+			return rng, opts, err
+		}
+	default:
+		return rng, opts, errors.New("query parameter year, or from and to, is required")
+	}
+
+	if m := q.Get("milestones"); m != "" {
+		var err error
+		if opts.Milestones, err = strconv.ParseBool(m); err != nil {
+			return rng, opts, fmt.Errorf("illegal milestones %q, want true or false", m)
+		}
+	}
+
+	for _, a := range q["alarm"] {
+		d, err := ics.ParseAlarm(a)
+		if err != nil {
+			return rng, opts, err
+		}
+		opts.Alarms = append(opts.Alarms, d)
+	}
+
+	return rng, opts, nil
+}
+
+func (s *Server) openAPI(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPI)
+}
+
+func list(r airac.Range) CycleList {
+	l := CycleList{Cycles: make([]airac.Info, 0, r.Len())}
+	for _, a := range r.Cycles() {
+		l.Cycles = append(l.Cycles, a.Info())
+	}
+	return l
+}
+
+// allowGet responds with 405 Method Not Allowed and returns false unless r is
+// a GET or HEAD request.
+func allowGet(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeJSON(w, http.StatusMethodNotAllowed, Error{Error: "method not allowed"})
+		return false
+	}
+	return true
+}
+
+// pathParam returns the single path segment following prefix.
+func pathParam(r *http.Request, prefix string) (string, error) {
+	param := strings.TrimPrefix(r.URL.Path, prefix)
+	if param == "" || strings.Contains(param, "/") {
+		return "", fmt.Errorf("%w: %s", errNotFound, r.URL.Path)
+	}
+	return param, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airacd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/internal/fakeclock"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	clock := fakeclock.New(time.Date(2021, time.February, 3, 12, 0, 0, 0, time.UTC))
+	ts := httptest.NewServer(New(Options{Clock: clock}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func get(t *testing.T, ts *httptest.Server, path string, v interface{}) int {
+	t.Helper()
+
+	resp, err := http.Get(ts.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("%s: want Content-Type application/json, got %q", path, ct)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+
+	return resp.StatusCode
+}
+
+func TestCycle(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+
+	testt := []struct {
+		path string
+		want string
+	}{
+		{"/v1/current", "2101"},
+		{"/v1/cycles/2014", "2014"},
+		{"/v1/dates/2021-01-28", "2101"},
+		{"/v1/dates/2021-01-27T23:59:59Z", "2014"},
+		{"/v1/dates/2021-01-28T00:30:00%2B01:00", "2014"},
+	}
+
+	for _, tt := range testt {
+		var got airac.Info
+		if code := get(t, ts, tt.path, &got); code != http.StatusOK {
+			t.Errorf("%s: want status %d, got %d", tt.path, http.StatusOK, code)
+		}
+		if want := airac.FromStringMust(tt.want).Info(); got.Ident != want.Ident || got.Effective != want.Effective {
+			t.Errorf("%s: want %+v, got %+v", tt.path, want, got)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+
+	testt := []struct {
+		path  string
+		first string
+		len   int
+	}{
+		{"/v1/years/2020", "2001", 14},
+		{"/v1/years/2021", "2101", 13},
+		{"/v1/ranges?from=2013&to=2102", "2013", 4},
+	}
+
+	for _, tt := range testt {
+		var got CycleList
+		if code := get(t, ts, tt.path, &got); code != http.StatusOK {
+			t.Errorf("%s: want status %d, got %d", tt.path, http.StatusOK, code)
+		}
+		if len(got.Cycles) != tt.len || got.Cycles[0].Ident != tt.first {
+			t.Errorf("%s: want %d cycles from %s, got %+v", tt.path, tt.len, tt.first, got)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+
+	testt := []struct {
+		path string
+		code int
+	}{
+		{"/v1/cycles/2115", http.StatusNotFound},
+		{"/v1/cycles/", http.StatusNotFound},
+		{"/v1/cycles/2101/extra", http.StatusNotFound},
+		{"/v1/dates/yesterday", http.StatusBadRequest},
+		{"/v1/years/20x0", http.StatusBadRequest},
+		{"/v1/ranges?from=2106&to=2101", http.StatusBadRequest},
+		{"/v1/ranges?from=2106", http.StatusBadRequest},
+		{"/v1/calendar.ics", http.StatusBadRequest},
+		{"/v1/calendar.ics?year=2021&from=2101&to=2102", http.StatusBadRequest},
+		{"/v1/calendar.ics?year=2021&alarm=soon", http.StatusBadRequest},
+		{"/v1/calendar.ics?year=2021&milestones=maybe", http.StatusBadRequest},
+		{"/v2/current", http.StatusNotFound},
+	}
+
+	for _, tt := range testt {
+		var got Error
+		if code := get(t, ts, tt.path, &got); code != tt.code {
+			t.Errorf("%s: want status %d, got %d", tt.path, tt.code, code)
+		}
+		if got.Error == "" {
+			t.Errorf("%s: want error message", tt.path)
+		}
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+
+	resp, err := http.Post(ts.URL+"/v1/current", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("want status %d, got %d", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+	if allow := resp.Header.Get("Allow"); allow != "GET, HEAD" {
+		t.Errorf("want Allow header, got %q", allow)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	if code := get(t, ts, "/v1/openapi.json", &doc); code != http.StatusOK {
+		t.Fatalf("want status %d, got %d", http.StatusOK, code)
+	}
+
+	for _, path := range []string{"/v1/current", "/v1/cycles/{ident}", "/v1/dates/{date}", "/v1/years/{year}", "/v1/ranges", "/v1/calendar.ics"} {
+		if _, ok := doc.Paths[path]; !ok {
+			t.Errorf("path %s not documented", path)
+		}
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t)
+
+	testt := []struct {
+		path   string
+		events int
+		alarms int
+	}{
+		{"/v1/calendar.ics?year=2020", 14, 0},
+		{"/v1/calendar.ics?from=2101&to=2102&milestones=true&alarm=7d&alarm=1h", 8, 16},
+	}
+
+	for _, tt := range testt {
+		resp, err := http.Get(ts.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: want status %d, got %d", tt.path, http.StatusOK, resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+			t.Errorf("%s: want Content-Type text/calendar, got %q", tt.path, ct)
+		}
+
+		got := string(body)
+		if n := strings.Count(got, "BEGIN:VEVENT"); n != tt.events {
+			t.Errorf("%s: want %d events, got %d", tt.path, tt.events, n)
+		}
+		if n := strings.Count(got, "BEGIN:VALARM"); n != tt.alarms {
+			t.Errorf("%s: want %d alarms, got %d", tt.path, tt.alarms, n)
+		}
+		if !strings.Contains(got, "DTSTAMP:20210203T120000Z\r\n") {
+			t.Errorf("%s: want DTSTAMP of the clock in\n%s", tt.path, got)
+		}
+	}
+}
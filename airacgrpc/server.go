@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package airacgrpc implements the AiracService gRPC service of package
+// github.com/jwkohnen/airac/proto/service.
+package airacgrpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/proto"
+	"github.com/jwkohnen/airac/proto/service"
+)
+
+const dateFormat = "2006-01-02"
+
+// Options configures a Server.
+type Options struct {
+	// Clock is the time source. If nil, airac.SystemClock is used.
+	Clock airac.Clock
+
+	// Recheck is passed on to the cycle tickers of WatchCycleChanges. Zero
+	// means one minute.
+	Recheck time.Duration
+}
+
+// Server implements service.AiracServiceServer.
+type Server struct {
+	service.UnimplementedAiracServiceServer
+
+	opts Options
+}
+
+// New returns a Server. Register it with service.RegisterAiracServiceServer.
+func New(opts Options) *Server {
+	if opts.Clock == nil {
+		opts.Clock = airac.SystemClock{}
+	}
+	return &Server{opts: opts}
+}
+
+// GetCurrent returns the cycle effective now.
+func (s *Server) GetCurrent(context.Context, *service.GetCurrentRequest) (*proto.AiracMessage, error) {
+	return airac.FromDate(s.opts.Clock.Now()).Proto(), nil
+}
+
+// FromDate returns the cycle effective at a date.
+func (s *Server) FromDate(_ context.Context, req *service.FromDateRequest) (*proto.AiracMessage, error) {
+	layout := time.RFC3339
+	if len(req.GetDate()) == len(dateFormat) {
+		layout = dateFormat
+	}
+
+	t, err := time.Parse(layout, req.GetDate())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "illegal date %q, want YYYY-MM-DD or RFC 3339", req.GetDate())
+	}
+
+	return airac.FromDate(t).Proto(), nil
+}
+
+// FromIdent returns the cycle with an identifier.
+func (s *Server) FromIdent(_ context.Context, req *service.FromIdentRequest) (*proto.AiracMessage, error) {
+	a, err := airac.FromString(req.GetIdent())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return a.Proto(), nil
+}
+
+// ListYear returns all cycles of a year.
+func (s *Server) ListYear(_ context.Context, req *service.ListYearRequest) (*service.AiracList, error) {
+	year := int(req.GetYear())
+	if year < 1902 || year > 2192 {
+		return nil, status.Errorf(codes.InvalidArgument, "illegal year %d, want 1902 to 2192", year)
+	}
+
+	return list(airac.CyclesInYear(year)), nil
+}
+
+// ListRange returns a range of cycles.
+func (s *Server) ListRange(_ context.Context, req *service.ListRangeRequest) (*service.AiracList, error) {
+	r, err := airac.ParseRange(req.GetFrom() + ".." + req.GetTo())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return list(r), nil
+}
+
+// WatchCycleChanges sends the current cycle, then each new cycle as it becomes
+// effective, until the client cancels.
+func (s *Server) WatchCycleChanges(req *service.WatchCycleChangesRequest, stream service.AiracService_WatchCycleChangesServer) error {
+	opts := airac.TickerOptions{
+		Clock:   s.opts.Clock,
+		Offset:  time.Duration(req.GetOffsetSeconds()) * time.Second,
+		Recheck: s.opts.Recheck,
+	}
+
+	ticker := airac.NewCycleTicker(stream.Context(), opts)
+	defer ticker.Stop()
+
+	if err := stream.Send(ticker.Current.Proto()); err != nil {
+		return err
+	}
+
+	for a := range ticker.C {
+		if err := stream.Send(a.Proto()); err != nil {
+			return err
+		}
+	}
+
+	return status.FromContextError(stream.Context().Err()).Err()
+}
+
+func list(r airac.Range) *service.AiracList {
+	l := &service.AiracList{Airacs: make([]*proto.AiracMessage, 0, r.Len())}
+	for _, a := range r.Cycles() {
+		l.Airacs = append(l.Airacs, a.Proto())
+	}
+	if !r.Empty() {
+		l.Range = r.Proto()
+	}
+	return l
+}
+
+// static assert
+var _ service.AiracServiceServer = (*Server)(nil)
@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airacgrpc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/internal/fakeclock"
+	"github.com/jwkohnen/airac/proto/service"
+)
+
+func newClient(t *testing.T, clock airac.Clock) service.AiracServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	service.RegisterAiracServiceServer(srv, New(Options{Clock: clock}))
+
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return service.NewAiracServiceClient(conn)
+}
+
+func idents(l *service.AiracList) []string {
+	var s []string
+	for _, m := range l.GetAiracs() {
+		s = append(s, airac.AIRAC(m.GetAirac19010110()).String())
+	}
+	return s
+}
+
+func TestUnary(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := fakeclock.New(time.Date(2021, time.February, 3, 12, 0, 0, 0, time.UTC))
+	c := newClient(t, clock)
+
+	cur, err := c.GetCurrent(ctx, &service.GetCurrentRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cur.GetIdent(); got != "2101" {
+		t.Errorf("GetCurrent: want 2101, got %s", got)
+	}
+	if got := cur.GetEffective().AsTime().Format("2006-01-02"); got != "2021-01-28" {
+		t.Errorf("GetCurrent: want effective 2021-01-28, got %s", got)
+	}
+
+	m, err := c.FromDate(ctx, &service.FromDateRequest{Date: "2021-01-27T23:59:59Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := airac.AIRAC(m.GetAirac19010110()).String(); got != "2014" {
+		t.Errorf("FromDate: want 2014, got %s", got)
+	}
+
+	m, err = c.FromIdent(ctx, &service.FromIdentRequest{Ident: "2014"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.GetAirac19010110(), uint32(airac.FromStringMust("2014")); got != want {
+		t.Errorf("FromIdent: want %d, got %d", want, got)
+	}
+
+	year, err := c.ListYear(ctx, &service.ListYearRequest{Year: 2020})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := idents(year); len(got) != 14 || got[13] != "2014" {
+		t.Errorf("ListYear: want 14 cycles up to 2014, got %v", got)
+	}
+
+	rng, err := c.ListRange(ctx, &service.ListRangeRequest{From: "2013", To: "2102"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := idents(rng), []string{"2013", "2014", "2101", "2102"}; len(got) != len(want) || got[0] != want[0] || got[3] != want[3] {
+		t.Errorf("ListRange: want %v, got %v", want, got)
+	}
+
+	r, err := airac.RangeFromProto(rng.GetRange())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.String(); got != "2013-2102" {
+		t.Errorf("ListRange: want range 2013-2102, got %s", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := newClient(t, nil)
+
+	testt := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"FromDate", func() error { _, err := c.FromDate(ctx, &service.FromDateRequest{Date: "yesterday"}); return err }, codes.InvalidArgument},
+		{"FromIdent", func() error { _, err := c.FromIdent(ctx, &service.FromIdentRequest{Ident: "2115"}); return err }, codes.NotFound},
+		{"ListYear", func() error { _, err := c.ListYear(ctx, &service.ListYearRequest{Year: 1}); return err }, codes.InvalidArgument},
+		{"ListRange", func() error {
+			_, err := c.ListRange(ctx, &service.ListRangeRequest{From: "2106", To: "2101"})
+			return err
+		}, codes.InvalidArgument},
+	}
+
+	for _, tt := range testt {
+		if got := status.Code(tt.call()); got != tt.code {
+			t.Errorf("%s: want %s, got %s", tt.name, tt.code, got)
+		}
+	}
+}
+
+func TestWatchCycleChanges(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Date(2021, time.January, 27, 12, 0, 0, 0, time.UTC))
+	c := newClient(t, clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.WatchCycleChanges(ctx, &service.WatchCycleChangesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"2014", "2101", "2102"} {
+		m, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := airac.AIRAC(m.GetAirac19010110()).String(); got != want {
+			t.Errorf("want %s, got %s", want, got)
+		}
+
+		clock.BlockUntil(1)
+		clock.Advance(28 * 24 * time.Hour)
+	}
+
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("want %s, got %v", codes.Canceled, err)
+	}
+}
+
+// steppingClock sets the wall clock to next after the first call of Now.
+type steppingClock struct {
+	*fakeclock.Clock
+
+	once sync.Once
+	next time.Time
+}
+
+func (c *steppingClock) Now() time.Time {
+	now := c.Clock.Now()
+	c.once.Do(func() { c.Clock.Set(c.next) })
+	return now
+}
+
+func TestWatchCycleChangesBoundary(t *testing.T) {
+	t.Parallel()
+
+	// the boundary to 2101 passes right after the ticker read the clock
+	clock := &steppingClock{
+		Clock: fakeclock.New(time.Date(2021, time.January, 27, 23, 59, 59, 0, time.UTC)),
+		next:  time.Date(2021, time.January, 28, 0, 0, 1, 0, time.UTC),
+	}
+	c := newClient(t, clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.WatchCycleChanges(ctx, &service.WatchCycleChangesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"2014", "2101", "2102"} {
+		m, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := m.GetIdent(); got != want {
+			t.Fatalf("want %s, got %s", want, got)
+		}
+
+		clock.BlockUntil(1)
+		if want == "2014" {
+			clock.Advance(airac.DefaultRecheck)
+		} else {
+			clock.Advance(28 * 24 * time.Hour)
+		}
+	}
+}
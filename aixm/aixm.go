@@ -0,0 +1,436 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package aixm checks that the permanent changes of AIXM 5.1 data take effect
+// on AIRAC effective dates.
+//
+// The checker streams through an AIXM 5.1 document, e.g. an AIXMBasicMessage,
+// and maps each BASELINE and PERMDELTA time slice of each feature to the AIRAC
+// cycle it starts in. The begin of the gml:validTime of such a time slice, and
+// the begin and end of its aixm:featureLifetime, must be on an AIRAC effective
+// date, either at 00:00 UTC or, following ICAO DOC 8126, 6th edition,
+// paragraph 2.6.4, at 00:01 UTC. Temporary time slices (TEMPDELTA) and
+// snapshots (SNAPSHOT) are not subject to the AIRAC schedule and are only
+// counted.
+package aixm
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jwkohnen/airac"
+)
+
+const (
+	nsGML = "http://www.opengis.net/gml/3.2"
+
+	// nsAIXM is the prefix of the namespaces of AIXM 5.1 and 5.1.1.
+	nsAIXM = "http://www.aixm.aero/schema/5.1"
+
+	dateFormat = "2006-01-02"
+)
+
+// Interpretations of permanent time slices.
+const (
+	Baseline  = "BASELINE"
+	PermDelta = "PERMDELTA"
+)
+
+// Feature is the result of checking the time slices of an AIXM feature.
+//
+// The time slices of a feature may be spread over several feature elements,
+// e.g. a BASELINE in one message:hasMember and a later PERMDELTA in another.
+// CheckStream reports each element on its own, Check merges all elements with
+// the same identifier into one Feature.
+type Feature struct {
+	// Identifier is the gml:identifier of the feature, usually a UUID.
+	Identifier string
+
+	// Type is the feature type, e.g. AirportHeliport.
+	Type string
+
+	// Line is the line of the start of the (first) feature element.
+	Line int
+
+	// Slices are the BASELINE and PERMDELTA time slices in document order.
+	Slices []Slice
+
+	// Skipped counts the other time slices, i.e. TEMPDELTA and SNAPSHOT.
+	Skipped int
+}
+
+// OK reports whether all permanent time slices are aligned to the AIRAC
+// schedule.
+func (f Feature) OK() bool {
+	for _, s := range f.Slices {
+		if !s.OK() {
+			return false
+		}
+	}
+	return true
+}
+
+// Slice is the result of checking a BASELINE or PERMDELTA time slice.
+type Slice struct {
+	// Line is the line of the start of the time slice.
+	Line int
+
+	// Interpretation is BASELINE or PERMDELTA.
+	Interpretation string
+
+	// Sequence and Correction are the sequence number and correction number
+	// of the time slice.
+	Sequence, Correction int
+
+	// Begin is the begin of the gml:validTime.
+	Begin time.Time
+
+	// Cycle is the AIRAC cycle Begin falls into. A begin at 00:00 or 00:01
+	// UTC on an effective date maps to the cycle effective on that date.
+	Cycle airac.AIRAC
+
+	// LifetimeBegin and LifetimeEnd are the begin and end of the
+	// aixm:featureLifetime. They are zero if not given or indeterminate.
+	LifetimeBegin time.Time
+	LifetimeEnd   time.Time
+
+	// Problems describes each time that is not on an AIRAC effective date.
+	Problems []string
+}
+
+// OK reports whether the time slice is aligned to the AIRAC schedule.
+func (s Slice) OK() bool { return len(s.Problems) == 0 }
+
+// Report is the result of Check.
+type Report struct {
+	Features []Feature
+}
+
+// Slices returns the number of checked time slices.
+func (r Report) Slices() int {
+	n := 0
+	for _, f := range r.Features {
+		n += len(f.Slices)
+	}
+	return n
+}
+
+// Misaligned returns the number of time slices that are not aligned.
+func (r Report) Misaligned() int {
+	n := 0
+	for _, f := range r.Features {
+		for _, s := range f.Slices {
+			if !s.OK() {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// OK reports whether all features are OK.
+func (r Report) OK() bool { return r.Misaligned() == 0 }
+
+// Check reads an AIXM 5.1 document and returns the report of all features
+// with time slices, one per feature identifier in the order of their first
+// appearance. Elements without an identifier are reported on their own.
+func Check(r io.Reader) (Report, error) {
+	var rep Report
+	index := make(map[string]int)
+
+	err := CheckStream(r, func(f Feature) error {
+		i, ok := index[f.Identifier]
+		if !ok || f.Identifier == "" {
+			index[f.Identifier] = len(rep.Features)
+			rep.Features = append(rep.Features, f)
+			return nil
+		}
+
+		rep.Features[i].Slices = append(rep.Features[i].Slices, f.Slices...)
+		rep.Features[i].Skipped += f.Skipped
+		return nil
+	})
+
+	return rep, err
+}
+
+// CheckStream reads an AIXM 5.1 document and calls fn for each feature element
+// with time slices as soon as the end of the element has been read, so a
+// feature whose time slices are spread over several elements is reported once
+// per element. It stops at the first error returned by fn.
+func CheckStream(r io.Reader, fn func(Feature) error) error {
+	c := checker{dec: xml.NewDecoder(r), fn: fn}
+	return c.run()
+}
+
+// element is an open element of the document.
+type element struct {
+	name xml.Name
+	line int
+
+	// identifier is the gml:identifier of the element, if any.
+	identifier string
+
+	// feature collects the time slices if the element is a feature.
+	feature *Feature
+}
+
+type checker struct {
+	dec   *xml.Decoder
+	fn    func(Feature) error
+	stack []element
+
+	slice *sliceState
+}
+
+// sliceState collects the values of the time slice being read.
+type sliceState struct {
+	line           int
+	interpretation string
+	sequence       string
+	correction     string
+	begin          string
+	lifetimeBegin  string
+	lifetimeEnd    string
+}
+
+func (c *checker) run() error {
+	for {
+		tok, err := c.dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			line, _ := c.dec.InputPos()
+			if err := c.start(t, line); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if err := c.end(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (c *checker) start(t xml.StartElement, line int) error {
+	c.stack = append(c.stack, element{name: t.Name, line: line})
+	parent := c.parent(1)
+
+	switch {
+	case is(t.Name, nsGML, "identifier") && parent != nil && isAIXM(parent.name):
+		text, err := c.text()
+		if err != nil {
+			return err
+		}
+		parent.identifier = strings.TrimSpace(text)
+
+	case is(t.Name, nsAIXM, "timeSlice") && parent != nil:
+		if parent.feature == nil {
+			parent.feature = &Feature{Identifier: parent.identifier, Type: parent.name.Local, Line: parent.line}
+		}
+		c.slice = &sliceState{line: line}
+
+	case c.slice == nil:
+
+	case is(t.Name, nsAIXM, "interpretation"):
+		return c.textTo(&c.slice.interpretation)
+	case is(t.Name, nsAIXM, "sequenceNumber"):
+		return c.textTo(&c.slice.sequence)
+	case is(t.Name, nsAIXM, "correctionNumber"):
+		return c.textTo(&c.slice.correction)
+
+	case is(t.Name, nsGML, "beginPosition"), is(t.Name, nsGML, "timePosition"):
+		switch {
+		case c.within(nsAIXM, "featureLifetime"):
+			return c.textTo(&c.slice.lifetimeBegin)
+		case c.within(nsGML, "validTime"):
+			return c.textTo(&c.slice.begin)
+		}
+	case is(t.Name, nsGML, "endPosition"):
+		if c.within(nsAIXM, "featureLifetime") {
+			return c.textTo(&c.slice.lifetimeEnd)
+		}
+	}
+
+	return nil
+}
+
+func (c *checker) end() error {
+	e := c.stack[len(c.stack)-1]
+	c.stack = c.stack[:len(c.stack)-1]
+
+	switch {
+	case is(e.name, nsAIXM, "timeSlice") && c.slice != nil:
+		feature := c.parent(0).feature
+		s, permanent, err := c.slice.check()
+		if err != nil {
+			return err
+		}
+		if permanent {
+			feature.Slices = append(feature.Slices, s)
+		} else {
+			feature.Skipped++
+		}
+		c.slice = nil
+
+	case e.feature != nil:
+		return c.fn(*e.feature)
+	}
+
+	return nil
+}
+
+// parent returns the open element n levels above the current one.
+func (c *checker) parent(n int) *element {
+	i := len(c.stack) - 1 - n
+	if i < 0 {
+		return nil
+	}
+	return &c.stack[i]
+}
+
+// within reports whether an open element has the given name.
+func (c *checker) within(space, local string) bool {
+	for i := len(c.stack) - 1; i >= 0; i-- {
+		if is(c.stack[i].name, space, local) {
+			return true
+		}
+	}
+	return false
+}
+
+// text reads the character data of the current element up to and including
+// its end.
+func (c *checker) text() (string, error) {
+	var b strings.Builder
+	for depth := 0; ; {
+		tok, err := c.dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			if depth == 0 {
+				b.Write(t)
+			}
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				c.stack = c.stack[:len(c.stack)-1]
+				return b.String(), nil
+			}
+			depth--
+		}
+	}
+}
+
+func (c *checker) textTo(s *string) error {
+	text, err := c.text()
+	*s = strings.TrimSpace(text)
+	return err
+}
+
+func (s *sliceState) check() (Slice, bool, error) {
+	interpretation := strings.ToUpper(s.interpretation)
+	if interpretation != Baseline && interpretation != PermDelta {
+		return Slice{}, false, nil
+	}
+
+	slice := Slice{Line: s.line, Interpretation: interpretation}
+
+	var err error
+	if s.sequence != "" {
+		if slice.Sequence, err = strconv.Atoi(s.sequence); err != nil {
+			return slice, true, fmt.Errorf("line %d: illegal sequenceNumber %q", s.line, s.sequence)
+		}
+	}
+	if s.correction != "" {
+		if slice.Correction, err = strconv.Atoi(s.correction); err != nil {
+			return slice, true, fmt.Errorf("line %d: illegal correctionNumber %q", s.line, s.correction)
+		}
+	}
+
+	if slice.Begin, err = parseTime(s.begin); err != nil {
+		return slice, true, fmt.Errorf("line %d: validTime: %w", s.line, err)
+	}
+	if slice.LifetimeBegin, err = parseTime(s.lifetimeBegin); err != nil {
+		return slice, true, fmt.Errorf("line %d: featureLifetime: %w", s.line, err)
+	}
+	if slice.LifetimeEnd, err = parseTime(s.lifetimeEnd); err != nil {
+		return slice, true, fmt.Errorf("line %d: featureLifetime: %w", s.line, err)
+	}
+
+	if slice.Begin.IsZero() {
+		slice.Problems = append(slice.Problems, "validTime has no begin")
+	} else {
+		slice.Cycle = airac.FromDate(slice.Begin)
+		slice.Problems = appendProblem(slice.Problems, "validTime begin", slice.Begin)
+	}
+	slice.Problems = appendProblem(slice.Problems, "featureLifetime begin", slice.LifetimeBegin)
+	slice.Problems = appendProblem(slice.Problems, "featureLifetime end", slice.LifetimeEnd)
+
+	return slice, true, nil
+}
+
+// appendProblem appends a problem if t is neither zero nor 00:00 or 00:01 UTC
+// on an effective date. Cycles change at midnight here, so that both times
+// map to the cycle of the effective date.
+func appendProblem(problems []string, what string, t time.Time) []string {
+	a := airac.FromDate(t)
+	if t.IsZero() || t.Equal(a.Effective()) || t.Equal(a.EffectivePrecise()) {
+		return problems
+	}
+
+	return append(problems, fmt.Sprintf("%s %s is not on an AIRAC effective date (%s effective %s, %s effective %s)",
+		what, t.Format(time.RFC3339), a, a.Effective().Format(dateFormat), a+1, (a+1).Effective().Format(dateFormat)))
+}
+
+// nolint:gochecknoglobals
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"}
+
+// parseTime parses an xsd:dateTime. Times without zone are UTC. An empty
+// string, e.g. of an indeterminate position, yields the zero time.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("illegal time %q", s)
+}
+
+func is(n xml.Name, space, local string) bool {
+	return n.Local == local && strings.HasPrefix(n.Space, space)
+}
+
+func isAIXM(n xml.Name) bool {
+	return strings.HasPrefix(n.Space, nsAIXM)
+}
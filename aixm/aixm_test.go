@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aixm
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// nolint:funlen
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("testdata/message.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	rep, err := Check(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := len(rep.Features), 2; got != want {
+		t.Fatalf("want %d features, got %d", want, got)
+	}
+	if got, want := rep.Slices(), 3; got != want {
+		t.Errorf("want %d slices, got %d", want, got)
+	}
+	if got, want := rep.Misaligned(), 1; got != want {
+		t.Errorf("want %d misaligned slices, got %d", want, got)
+	}
+
+	ah := rep.Features[0]
+	if ah.Identifier != "dd062d88-3e64-4a5d-bebd-89476db9ebea" || ah.Type != "AirportHeliport" || ah.Line != 7 {
+		t.Errorf("want AirportHeliport dd062d88-... in line 7, got %s %s in line %d", ah.Type, ah.Identifier, ah.Line)
+	}
+	if !ah.OK() || ah.Skipped != 1 || len(ah.Slices) != 2 {
+		t.Errorf("want OK with 2 slices and 1 skipped, got %t, %d, %d", ah.OK(), len(ah.Slices), ah.Skipped)
+	}
+
+	testt := []struct {
+		slice          Slice
+		interpretation string
+		sequence       int
+		cycle          string
+	}{
+		{ah.Slices[0], Baseline, 1, "2101"},
+		{ah.Slices[1], PermDelta, 2, "2102"},
+		{rep.Features[1].Slices[0], PermDelta, 4, "2102"},
+	}
+
+	for _, tt := range testt {
+		if tt.slice.Interpretation != tt.interpretation || tt.slice.Sequence != tt.sequence || tt.slice.Cycle.String() != tt.cycle {
+			t.Errorf("want %s %d in %s, got %s %d in %s", tt.interpretation, tt.sequence, tt.cycle,
+				tt.slice.Interpretation, tt.slice.Sequence, tt.slice.Cycle)
+		}
+	}
+
+	nav := rep.Features[1]
+	if nav.OK() || nav.Type != "Navaid" {
+		t.Errorf("want Navaid not OK, got %s %t", nav.Type, nav.OK())
+	}
+	if got := nav.Slices[0].Correction; got != 1 {
+		t.Errorf("want correction 1, got %d", got)
+	}
+
+	want := []string{
+		"validTime begin 2021-03-01T00:00:00Z is not on an AIRAC effective date (2102 effective 2021-02-25, 2103 effective 2021-03-25)",
+		"featureLifetime end 2021-04-22T12:00:00Z is not on an AIRAC effective date (2104 effective 2021-04-22, 2105 effective 2021-05-20)",
+	}
+	if got := nav.Slices[0].Problems; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("want:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestCheckGroupsByIdentifier(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("testdata/members.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var elements int
+	if err := CheckStream(bytes.NewReader(b), func(Feature) error { elements++; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if elements != 3 {
+		t.Errorf("want 3 feature elements streamed, got %d", elements)
+	}
+
+	rep, err := Check(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(rep.Features), 2; got != want {
+		t.Fatalf("want %d features, got %d", want, got)
+	}
+
+	rw := rep.Features[0]
+	if rw.Identifier != "5b2a3f4e-0c1d-4e7f-9a8b-1c2d3e4f5a6b" || rw.Line != 7 || len(rw.Slices) != 2 || rw.Skipped != 1 {
+		t.Errorf("want 5b2a3f4e-... in line 7 with 2 slices and 1 skipped, got %s in line %d with %d and %d",
+			rw.Identifier, rw.Line, len(rw.Slices), rw.Skipped)
+	}
+	if rw.OK() || rw.Slices[0].Interpretation != Baseline || rw.Slices[1].Interpretation != PermDelta {
+		t.Errorf("want BASELINE and misaligned PERMDELTA, got OK %v, %+v", rw.OK(), rw.Slices)
+	}
+	if !rep.Features[1].OK() || rep.Misaligned() != 1 || rep.Slices() != 3 {
+		t.Errorf("want second feature OK, 1 of 3 slices misaligned, got %v, %d of %d",
+			rep.Features[1].OK(), rep.Misaligned(), rep.Slices())
+	}
+}
+
+func TestCheckStreamStops(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("testdata/message.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	n := 0
+	stop := errors.New("stop")
+	err = CheckStream(f, func(Feature) error {
+		n++
+		return stop
+	})
+	if !errors.Is(err, stop) || n != 1 {
+		t.Errorf("want %v after 1 feature, got %v after %d", stop, err, n)
+	}
+}
+
+func TestCheckErrors(t *testing.T) {
+	t.Parallel()
+
+	slice := func(begin string) string {
+		return `<aixm:Navaid xmlns:aixm="http://www.aixm.aero/schema/5.1" xmlns:gml="http://www.opengis.net/gml/3.2">
+  <aixm:timeSlice><aixm:NavaidTimeSlice>
+    <gml:validTime><gml:TimePeriod><gml:beginPosition>` + begin + `</gml:beginPosition></gml:TimePeriod></gml:validTime>
+    <aixm:interpretation>BASELINE</aixm:interpretation>
+  </aixm:NavaidTimeSlice></aixm:timeSlice>
+</aixm:Navaid>`
+	}
+
+	if _, err := Check(strings.NewReader(slice("yesterday"))); err == nil {
+		t.Error("illegal time: want error, got nil")
+	}
+	if _, err := Check(strings.NewReader(slice("2021-01-28")[:100])); err == nil {
+		t.Error("truncated: want error, got nil")
+	}
+
+	rep, err := Check(strings.NewReader(slice("")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rep.Features[0].Slices[0].Problems; len(got) != 1 || got[0] != "validTime has no begin" {
+		t.Errorf("want missing begin, got %v", got)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"fmt"
+	"time"
+)
+
+// IsEffectiveDate reports whether t falls on an effective date, i.e. whether
+// an AIRAC cycle becomes effective on the UTC calendar day of t. The time of
+// day is ignored.
+func IsEffectiveDate(t time.Time) bool {
+	d := utcDate(t)
+	return FromDate(d).Effective().Equal(d)
+}
+
+// IsEffectiveDatePrecise reports whether t is the instant an AIRAC cycle
+// becomes effective, i.e. 00:01 UTC on an effective date.
+func IsEffectiveDatePrecise(t time.Time) bool {
+	return FromDatePrecise(t).EffectivePrecise().Equal(t)
+}
+
+// AlignDown returns the AIRAC cycle that is effective at t, i.e. the cycle
+// with the latest effective date at or before t. It equals FromDate.
+func AlignDown(t time.Time) AIRAC {
+	return FromDate(t)
+}
+
+// AlignUp returns the AIRAC cycle with the earliest effective date at or after
+// t.
+func AlignUp(t time.Time) AIRAC {
+	a := FromDate(t)
+	if a.Effective().Equal(t) {
+		return a
+	}
+	return a + 1
+}
+
+// Nearest returns the AIRAC cycle whose effective date is nearest to t. A tie
+// resolves to the later cycle.
+func Nearest(t time.Time) AIRAC {
+	return nearest(t, AlignDown(t), AlignUp(t), AIRAC.Effective)
+}
+
+// AlignDownPrecise is like AlignDown, but cycles become effective at 00:01
+// UTC. It equals FromDatePrecise.
+func AlignDownPrecise(t time.Time) AIRAC {
+	return FromDatePrecise(t)
+}
+
+// AlignUpPrecise is like AlignUp, but cycles become effective at 00:01 UTC.
+func AlignUpPrecise(t time.Time) AIRAC {
+	a := FromDatePrecise(t)
+	if a.EffectivePrecise().Equal(t) {
+		return a
+	}
+	return a + 1
+}
+
+// NearestPrecise is like Nearest, but cycles become effective at 00:01 UTC.
+func NearestPrecise(t time.Time) AIRAC {
+	return nearest(t, AlignDownPrecise(t), AlignUpPrecise(t), AIRAC.EffectivePrecise)
+}
+
+func nearest(t time.Time, down, up AIRAC, effective func(AIRAC) time.Time) AIRAC {
+	if t.Sub(effective(down)) < effective(up).Sub(t) {
+		return down
+	}
+	return up
+}
+
+// Deviation describes how many days a date is off the AIRAC schedule.
+type Deviation struct {
+	// Date is the UTC calendar day of the examined time at 00:00 UTC.
+	Date time.Time
+
+	// Previous is the cycle effective on Date, and Next is the cycle
+	// following it.
+	Previous AIRAC
+	Next     AIRAC
+
+	// DaysAfter is the number of days Date is after the effective date of
+	// Previous; zero if Date is an effective date. DaysBefore is the number
+	// of days Date is before the effective date of Next.
+	DaysAfter  int
+	DaysBefore int
+}
+
+// DaysOff returns how many days the UTC calendar day of t is off the effective
+// dates of the AIRAC cycles around it. The time of day is ignored.
+func DaysOff(t time.Time) Deviation {
+	d := utcDate(t)
+	prev := FromDate(d)
+
+	return Deviation{
+		Date:       d,
+		Previous:   prev,
+		Next:       prev + 1,
+		DaysAfter:  int(d.Sub(prev.Effective()).Hours() / 24),
+		DaysBefore: int((prev + 1).Effective().Sub(d).Hours() / 24),
+	}
+}
+
+// Aligned reports whether Date is an effective date.
+func (d Deviation) Aligned() bool { return d.DaysAfter == 0 }
+
+// String explains the deviation, e.g. "2021-01-30 is 2 days after 2101
+// (effective 2021-01-28) and 26 days before 2102 (effective 2021-02-25)".
+func (d Deviation) String() string {
+	if d.Aligned() {
+		return fmt.Sprintf("%s is the effective date of %s", d.Date.Format(format), d.Previous)
+	}
+	return fmt.Sprintf("%s is %s after %s (effective %s) and %s before %s (effective %s)",
+		d.Date.Format(format),
+		days(d.DaysAfter), d.Previous, d.Previous.Effective().Format(format),
+		days(d.DaysBefore), d.Next, d.Next.Effective().Format(format))
+}
+
+func days(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// utcDate returns 00:00 UTC of the UTC calendar day of t.
+func utcDate(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIsEffectiveDate(t *testing.T) {
+	t.Parallel()
+
+	cet := time.FixedZone("CET", 3600)
+
+	testt := []struct {
+		t       time.Time
+		want    bool
+		precise bool
+	}{
+		{date("2021-01-28"), true, false},
+		{date("2021-01-28").Add(time.Minute), true, true},
+		{date("2021-01-28").Add(23 * time.Hour), true, false},
+		{date("2021-01-27"), false, false},
+		{date("2021-01-29"), false, false},
+		{date("2020-12-31"), true, false},
+		{time.Date(2021, time.January, 28, 0, 30, 0, 0, cet), false, false}, // 2021-01-27 23:30 UTC
+		{time.Date(2021, time.January, 28, 1, 1, 0, 0, cet), true, true},
+	}
+
+	for _, tt := range testt {
+		if got := IsEffectiveDate(tt.t); got != tt.want {
+			t.Errorf("%s: want %t, got %t", tt.t, tt.want, got)
+		}
+		if got := IsEffectiveDatePrecise(tt.t); got != tt.precise {
+			t.Errorf("%s precise: want %t, got %t", tt.t, tt.precise, got)
+		}
+	}
+}
+
+func TestAlign(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		t                 time.Time
+		down, up, nearest string
+	}{
+		{date("2021-01-28"), "2101", "2101", "2101"},
+		{date("2021-01-28").Add(time.Nanosecond), "2101", "2102", "2101"},
+		{date("2021-02-10"), "2101", "2102", "2101"},
+		{date("2021-02-11"), "2101", "2102", "2102"}, // tie
+		{date("2021-02-24"), "2101", "2102", "2102"},
+		{date("2021-02-25").Add(-time.Nanosecond), "2101", "2102", "2102"},
+	}
+
+	for _, tt := range testt {
+		if got := AlignDown(tt.t).String(); got != tt.down {
+			t.Errorf("AlignDown(%s): want %s, got %s", tt.t, tt.down, got)
+		}
+		if got := AlignUp(tt.t).String(); got != tt.up {
+			t.Errorf("AlignUp(%s): want %s, got %s", tt.t, tt.up, got)
+		}
+		if got := Nearest(tt.t).String(); got != tt.nearest {
+			t.Errorf("Nearest(%s): want %s, got %s", tt.t, tt.nearest, got)
+		}
+	}
+}
+
+func TestAlignPrecise(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		t                 time.Time
+		down, up, nearest string
+	}{
+		{date("2021-01-28"), "2014", "2101", "2101"},
+		{date("2021-01-28").Add(time.Minute), "2101", "2101", "2101"},
+		{date("2021-01-28").Add(2 * time.Minute), "2101", "2102", "2101"},
+		{date("2021-02-11").Add(time.Minute), "2101", "2102", "2102"}, // tie
+		{date("2021-02-11"), "2101", "2102", "2101"},
+	}
+
+	for _, tt := range testt {
+		if got := AlignDownPrecise(tt.t).String(); got != tt.down {
+			t.Errorf("AlignDownPrecise(%s): want %s, got %s", tt.t, tt.down, got)
+		}
+		if got := AlignUpPrecise(tt.t).String(); got != tt.up {
+			t.Errorf("AlignUpPrecise(%s): want %s, got %s", tt.t, tt.up, got)
+		}
+		if got := NearestPrecise(tt.t).String(); got != tt.nearest {
+			t.Errorf("NearestPrecise(%s): want %s, got %s", tt.t, tt.nearest, got)
+		}
+	}
+}
+
+func TestDaysOff(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		t      time.Time
+		after  int
+		before int
+		want   string
+	}{
+		{date("2021-01-28").Add(12 * time.Hour), 0, 28, "2021-01-28 is the effective date of 2101"},
+		{date("2021-01-29"), 1, 27, "2021-01-29 is 1 day after 2101 (effective 2021-01-28) and 27 days before 2102 (effective 2021-02-25)"},
+		{date("2021-02-24"), 27, 1, "2021-02-24 is 27 days after 2101 (effective 2021-01-28) and 1 day before 2102 (effective 2021-02-25)"},
+	}
+
+	for _, tt := range testt {
+		d := DaysOff(tt.t)
+		if d.DaysAfter != tt.after || d.DaysBefore != tt.before || d.Aligned() != (tt.after == 0) {
+			t.Errorf("%s: want %d days after, %d before, got %d, %d", tt.t, tt.after, tt.before, d.DaysAfter, d.DaysBefore)
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("want %s, got %s", tt.want, got)
+		}
+	}
+}
+
+func ExampleDaysOff() {
+	proposed := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	fmt.Println(IsEffectiveDate(proposed))
+	fmt.Println(DaysOff(proposed))
+	fmt.Println(Nearest(proposed).Effective().Format("2006-01-02"))
+	// Output:
+	// false
+	// 2021-03-01 is 4 days after 2102 (effective 2021-02-25) and 24 days before 2103 (effective 2021-03-25)
+	// 2021-02-25
+}
@@ -0,0 +1,258 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package arinc424 reads and rewrites the AIRAC cycle fields of ARINC 424
+// navigation database files.
+//
+// An ARINC 424 file is a sequence of 132 column records, one per line. The
+// first record of the file is header 01, which states the cycle of the whole
+// file in columns 36 to 39. Data records state their cycle in columns 129 to
+// 132. Both use the form YYCC, i.e. the AIRAC identifier as parsed by
+// airac.FromString.
+package arinc424
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jwkohnen/airac"
+)
+
+// Columns of the cycle date fields, zero-based and half-open.
+const (
+	headerCycleStart = 35
+	headerCycleEnd   = 39
+	recordCycleStart = 128
+	recordCycleEnd   = 132
+)
+
+// RecordLength is the length of an ARINC 424 record without line break.
+const RecordLength = 132
+
+// Record is a record of an ARINC 424 file.
+type Record struct {
+	// Line is the line number of the record, starting at 1.
+	Line int
+
+	// Data is the record without line break. It is only valid until the
+	// next call of Read.
+	Data []byte
+
+	// Header reports whether the record is a header record (HDR).
+	Header bool
+
+	// Cycle is the cycle date of the record, if HasCycle. For header 01 it
+	// is the cycle of the file, for other header records HasCycle is false.
+	Cycle    airac.AIRAC
+	HasCycle bool
+}
+
+// ParseError is returned for records with an illegal cycle date.
+type ParseError struct {
+	Line   int    // line of the record, starting at 1
+	Column int    // column of the cycle date, starting at 1
+	Field  string // the cycle date as found
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: illegal cycle date %q: %v", e.Line, e.Column, e.Field, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Reader reads the records of an ARINC 424 file.
+type Reader struct {
+	sc   *bufio.Scanner
+	line int
+
+	header    airac.AIRAC
+	hasHeader bool
+}
+
+// NewReader returns a Reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{sc: bufio.NewScanner(r)}
+}
+
+// Read returns the next record. It returns io.EOF after the last record, and
+// a *ParseError if the cycle date of the record is illegal. Blank cycle date
+// fields and records too short to hold one are not an error; HasCycle is
+// false for them.
+func (r *Reader) Read() (*Record, error) {
+	for r.sc.Scan() {
+		r.line++
+
+		data := bytes.TrimRight(r.sc.Bytes(), "\r")
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		rec := &Record{Line: r.line, Data: data, Header: bytes.HasPrefix(data, []byte("HDR"))}
+
+		start, end := recordCycleStart, recordCycleEnd
+		if rec.Header {
+			if !bytes.HasPrefix(data, []byte("HDR01")) {
+				return rec, nil
+			}
+			start, end = headerCycleStart, headerCycleEnd
+		}
+
+		a, ok, err := parseCycle(data, start, end)
+		if err != nil {
+			return rec, &ParseError{Line: r.line, Column: start + 1, Field: string(data[start:end]), Err: err}
+		}
+		rec.Cycle, rec.HasCycle = a, ok
+
+		if rec.Header && ok {
+			r.header, r.hasHeader = a, true
+		}
+
+		return rec, nil
+	}
+
+	if err := r.sc.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// HeaderCycle returns the cycle of the file as stated in header 01, once it
+// has been read.
+func (r *Reader) HeaderCycle() (airac.AIRAC, bool) {
+	return r.header, r.hasHeader
+}
+
+func parseCycle(data []byte, start, end int) (airac.AIRAC, bool, error) {
+	if len(data) < end {
+		return 0, false, nil
+	}
+
+	field := data[start:end]
+	if len(bytes.TrimSpace(field)) == 0 {
+		return 0, false, nil
+	}
+
+	a, err := airac.FromString(string(field))
+	if err != nil {
+		return 0, false, err
+	}
+	return a, true, nil
+}
+
+// Mismatch is a record whose cycle date differs from the cycle of the file.
+type Mismatch struct {
+	Line   int
+	Cycle  airac.AIRAC
+	Header airac.AIRAC
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("line %d: cycle date %s, header states %s", m.Line, m.Cycle, m.Header)
+}
+
+// ErrNoHeader is returned by Check if the file has no header 01 with a cycle
+// date before the first data record.
+var ErrNoHeader = errors.New("no header 01 with cycle date") // nolint:gochecknoglobals
+
+// Check reads all records from r and returns the cycle of the file and the
+// records whose cycle date differs from it.
+func Check(r io.Reader) (airac.AIRAC, []Mismatch, error) {
+	rd := NewReader(r)
+
+	var mismatches []Mismatch
+	for {
+		rec, err := rd.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+
+		header, ok := rd.HeaderCycle()
+		if rec.Header || !rec.HasCycle {
+			continue
+		}
+		if !ok {
+			return 0, nil, fmt.Errorf("line %d: %w", rec.Line, ErrNoHeader)
+		}
+		if rec.Cycle != header {
+			mismatches = append(mismatches, Mismatch{Line: rec.Line, Cycle: rec.Cycle, Header: header})
+		}
+	}
+
+	header, ok := rd.HeaderCycle()
+	if !ok {
+		return 0, nil, ErrNoHeader
+	}
+
+	return header, mismatches, nil
+}
+
+// Restamp copies the ARINC 424 file from src to dst with the cycle date of
+// header 01 and of all records that carry one set to a. Everything else is
+// copied unchanged, including line breaks and blank cycle date fields. The
+// file CRC of the header is not updated. It returns the number of stamped
+// records, including the header.
+func Restamp(dst io.Writer, src io.Reader, a airac.AIRAC) (records int, err error) {
+	stamp := []byte(a.String())
+
+	br := bufio.NewReader(src)
+	bw := bufio.NewWriter(dst)
+
+	for line := 1; ; line++ {
+		data, err := br.ReadBytes('\n')
+		if len(data) > 0 {
+			body := bytes.TrimRight(data, "\r\n")
+
+			start, end := recordCycleStart, recordCycleEnd
+			if bytes.HasPrefix(body, []byte("HDR")) {
+				start, end = headerCycleStart, headerCycleEnd
+				if !bytes.HasPrefix(body, []byte("HDR01")) {
+					start = -1
+				}
+			}
+
+			if start >= 0 {
+				_, ok, perr := parseCycle(body, start, end)
+				if perr != nil {
+					return records, &ParseError{Line: line, Column: start + 1, Field: string(body[start:end]), Err: perr}
+				}
+				if ok {
+					copy(body[start:end], stamp)
+					records++
+				}
+			}
+
+			if _, werr := bw.Write(data); werr != nil {
+				return records, werr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return records, err
+		}
+	}
+
+	return records, bw.Flush()
+}
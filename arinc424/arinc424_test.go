@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package arinc424
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jwkohnen/airac"
+)
+
+// header returns a header 01 record stating cycle.
+func header(cycle string) string {
+	h := "HDR01" + "FAACIFP18      " + "001" + "P" + "0132" + "0000004" + cycle
+	return h + strings.Repeat(" ", RecordLength-len(h))
+}
+
+// record returns a data record with the cycle date field cycle.
+func record(ident, cycle string) string {
+	r := "SUSAP KSEAK1A" + ident
+	return r + strings.Repeat(" ", recordCycleStart-len(r)) + cycle
+}
+
+func file(lines ...string) string { return strings.Join(lines, "\r\n") + "\r\n" }
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	in := file(
+		header("2101"),
+		"HDR02"+strings.Repeat(" ", 127),
+		record("SEA", "2101"),
+		"",
+		record("BFI", "    "),
+		"SUSAP KSEAK1ASHORT",
+	)
+
+	rd := NewReader(strings.NewReader(in))
+
+	testt := []struct {
+		line     int
+		header   bool
+		hasCycle bool
+		cycle    string
+	}{
+		{1, true, true, "2101"},
+		{2, true, false, ""},
+		{3, false, true, "2101"},
+		{5, false, false, ""},
+		{6, false, false, ""},
+	}
+
+	for _, tt := range testt {
+		rec, err := rd.Read()
+		if err != nil {
+			t.Fatalf("line %d: %v", tt.line, err)
+		}
+		if rec.Line != tt.line || rec.Header != tt.header || rec.HasCycle != tt.hasCycle {
+			t.Errorf("want line %d, header %t, has cycle %t, got %d, %t, %t",
+				tt.line, tt.header, tt.hasCycle, rec.Line, rec.Header, rec.HasCycle)
+		}
+		if rec.HasCycle && rec.Cycle.String() != tt.cycle {
+			t.Errorf("line %d: want cycle %s, got %s", tt.line, tt.cycle, rec.Cycle)
+		}
+		if bytes.HasSuffix(rec.Data, []byte("\r")) {
+			t.Errorf("line %d: want data without line break", tt.line)
+		}
+	}
+
+	if _, err := rd.Read(); !errors.Is(err, io.EOF) {
+		t.Errorf("want %v, got %v", io.EOF, err)
+	}
+	if a, ok := rd.HeaderCycle(); !ok || a.String() != "2101" {
+		t.Errorf("want header cycle 2101, got %s, %t", a, ok)
+	}
+}
+
+func TestReaderParseError(t *testing.T) {
+	t.Parallel()
+
+	rd := NewReader(strings.NewReader(file(header("2101"), record("SEA", "21X1"))))
+	if _, err := rd.Read(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := rd.Read()
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("want *ParseError, got %v", err)
+	}
+	if perr.Line != 2 || perr.Column != 129 || perr.Field != "21X1" {
+		t.Errorf("want line 2, column 129, field 21X1, got %d, %d, %s", perr.Line, perr.Column, perr.Field)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	in := file(header("2101"), record("SEA", "2101"), record("BFI", "2014"), record("PAE", "2101"), record("TIW", "2102"))
+
+	a, mismatches, err := Check(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != "2101" {
+		t.Errorf("want 2101, got %s", a)
+	}
+
+	var got []string
+	for _, m := range mismatches {
+		got = append(got, m.String())
+	}
+	want := "line 3: cycle date 2014, header states 2101; line 5: cycle date 2102, header states 2101"
+	if strings.Join(got, "; ") != want {
+		t.Errorf("want %s, got %s", want, strings.Join(got, "; "))
+	}
+}
+
+func TestCheckNoHeader(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{file(record("SEA", "2101")), file(header("    ")), ""} {
+		if _, _, err := Check(strings.NewReader(in)); !errors.Is(err, ErrNoHeader) {
+			t.Errorf("want %v, got %v", ErrNoHeader, err)
+		}
+	}
+}
+
+func TestRestamp(t *testing.T) {
+	t.Parallel()
+
+	in := file(header("2101"), "HDR02"+strings.Repeat(" ", 127), record("SEA", "2101"), record("BFI", "    "), record("PAE", "2014"))
+	in = strings.TrimSuffix(in, "\r\n") // no line break after the last record
+
+	var out bytes.Buffer
+	n, err := Restamp(&out, strings.NewReader(in), airac.FromStringMust("2102"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("want 3 stamped records, got %d", n)
+	}
+
+	want := file(header("2102"), "HDR02"+strings.Repeat(" ", 127), record("SEA", "2102"), record("BFI", "    "), record("PAE", "2102"))
+	want = strings.TrimSuffix(want, "\r\n")
+	if out.String() != want {
+		t.Errorf("want:\n%q\ngot:\n%q", want, out.String())
+	}
+
+	a, mismatches, err := Check(&out)
+	if err != nil || a.String() != "2102" || len(mismatches) != 0 {
+		t.Errorf("want 2102 without mismatches, got %s, %v, %v", a, mismatches, err)
+	}
+}
+
+func TestRestampParseError(t *testing.T) {
+	t.Parallel()
+
+	_, err := Restamp(io.Discard, strings.NewReader(file(header("2101"), record("SEA", "2115"))), airac.FromStringMust("2102"))
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Line != 2 {
+		t.Errorf("want *ParseError in line 2, got %v", err)
+	}
+}
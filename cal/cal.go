@@ -0,0 +1,248 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cal renders the months of a year as a calendar grid in the style of
+// cal(1), with AIRAC effective dates highlighted and labelled with the
+// identifiers of their cycles.
+//
+// In plain text an effective date is enclosed in brackets, e.g. [28], and a
+// milestone date in parentheses, e.g. (17). Weeks start on Monday.
+package cal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jwkohnen/airac"
+)
+
+const (
+	// monthWidth is the width of a month block: seven cells of three
+	// characters and room for the closing mark of the last column.
+	monthWidth   = 7*3 + 1
+	monthsPerRow = 3
+	gutter       = "  "
+
+	ansiEffective = "\x1b[7m"
+	ansiMilestone = "\x1b[4m"
+	ansiReset     = "\x1b[0m"
+)
+
+// Options configures the rendering.
+type Options struct {
+	// Milestones marks and labels the milestone dates of the cycles, too.
+	Milestones bool
+
+	// ANSI highlights dates with ANSI escape sequences, reverse video for
+	// effective dates and underlined for milestones, instead of brackets
+	// and parentheses. Labels are always plain text.
+	ANSI bool
+}
+
+type mark int
+
+const (
+	none mark = iota
+	milestone
+	effective
+)
+
+type label struct {
+	day  int
+	mark mark
+	text string
+}
+
+// Write renders all months of year to w.
+func Write(w io.Writer, year int, opts Options) error {
+	var buf bytes.Buffer
+
+	title := fmt.Sprint(year)
+	width := monthsPerRow*monthWidth + (monthsPerRow-1)*len(gutter)
+	buf.WriteString(strings.TrimRight(center(title, width), " "))
+	buf.WriteString("\n\n")
+
+	effectives := make(map[time.Time]airac.AIRAC)
+	for _, a := range airac.CyclesInYear(year).Cycles() {
+		effectives[a.Effective()] = a
+	}
+
+	for first := time.January; first <= time.December; first += monthsPerRow {
+		var blocks [][]string
+		height := 0
+		for m := first; m < first+monthsPerRow; m++ {
+			block := monthBlock(year, m, effectives, opts)
+			if len(block) > height {
+				height = len(block)
+			}
+			blocks = append(blocks, block)
+		}
+
+		for i := 0; i < height; i++ {
+			var line strings.Builder
+			for j, block := range blocks {
+				if j > 0 {
+					line.WriteString(gutter)
+				}
+				if i < len(block) {
+					line.WriteString(block[i])
+				} else {
+					line.WriteString(strings.Repeat(" ", monthWidth))
+				}
+			}
+			buf.WriteString(strings.TrimRight(line.String(), " "))
+			buf.WriteByte('\n')
+		}
+		buf.WriteByte('\n')
+	}
+
+	_, err := w.Write(bytes.TrimRight(buf.Bytes(), "\n"))
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// monthBlock returns the lines of a month, each monthWidth characters wide
+// (not counting ANSI escape sequences).
+func monthBlock(year int, month time.Month, effectives map[time.Time]airac.AIRAC, opts Options) []string {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	days := first.AddDate(0, 1, -1).Day()
+
+	lines := []string{
+		center(month.String(), monthWidth),
+		pad(" Mo Tu We Th Fr Sa Su"),
+	}
+
+	var labels []label
+	marks := make([]mark, days+1)
+	for d := 1; d <= days; d++ {
+		date := first.AddDate(0, 0, d-1)
+
+		if a, ok := effectives[date]; ok {
+			marks[d] = effective
+			labels = append(labels, label{day: d, mark: effective, text: a.String()})
+		}
+
+		if !opts.Milestones {
+			continue
+		}
+		for _, m := range airac.Milestones() {
+			a := airac.FromDate(date.Add(-m.Offset()))
+			if !a.Milestone(m).Equal(date) {
+				continue
+			}
+			if marks[d] == none {
+				marks[d] = milestone
+			}
+			labels = append(labels, label{day: d, mark: milestone, text: a.String() + " " + short(m)})
+		}
+	}
+
+	// Monday is column 0
+	col := (int(first.Weekday()) + 6) % 7
+	var week [7]int
+	for d := 1; d <= days; d++ {
+		week[col] = d
+		col++
+		if col == 7 || d == days {
+			lines = append(lines, weekLine(week, marks, opts.ANSI))
+			week, col = [7]int{}, 0
+		}
+	}
+
+	// align the labels of neighbouring months
+	for len(lines) < 2+6 {
+		lines = append(lines, pad(""))
+	}
+	if len(labels) > 0 {
+		lines = append(lines, pad(""))
+	}
+	sort.SliceStable(labels, func(i, j int) bool { return labels[i].day < labels[j].day })
+	for _, l := range labels {
+		open, closing := "(", ")"
+		if l.mark == effective {
+			open, closing = "[", "]"
+		}
+		lines = append(lines, pad(fmt.Sprintf(" %s%02d%s %s", open, l.day, closing, l.text)))
+	}
+
+	return lines
+}
+
+// weekLine renders a week; days are zero for blank cells.
+func weekLine(week [7]int, marks []mark, ansi bool) string {
+	if ansi {
+		var b strings.Builder
+		for _, d := range week {
+			b.WriteByte(' ')
+			switch {
+			case d == 0:
+				b.WriteString("  ")
+			case marks[d] == effective:
+				fmt.Fprintf(&b, "%s%2d%s", ansiEffective, d, ansiReset)
+			case marks[d] == milestone:
+				fmt.Fprintf(&b, "%s%2d%s", ansiMilestone, d, ansiReset)
+			default:
+				fmt.Fprintf(&b, "%2d", d)
+			}
+		}
+		b.WriteByte(' ')
+		return b.String()
+	}
+
+	line := []byte(strings.Repeat(" ", monthWidth))
+	for c, d := range week {
+		if d == 0 {
+			continue
+		}
+		copy(line[3*c+1:], fmt.Sprintf("%2d", d))
+		switch marks[d] {
+		case effective:
+			line[3*c], line[3*c+3] = '[', ']'
+		case milestone:
+			line[3*c], line[3*c+3] = '(', ')'
+		}
+	}
+
+	return string(line)
+}
+
+// short returns a label of a milestone that fits into a month block.
+func short(m airac.Milestone) string {
+	if m == airac.SubmissionCutOff {
+		return "cut-off"
+	}
+	return m.String()
+}
+
+func center(s string, width int) string {
+	left := (width - len(s)) / 2
+	right := width - len(s) - left
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", right)
+}
+
+func pad(s string) string {
+	if len(s) >= monthWidth {
+		return s
+	}
+	return s + strings.Repeat(" ", monthWidth-len(s))
+}
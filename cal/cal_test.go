@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func render(t *testing.T, year int, opts Options) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := Write(&buf, year, opts); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	got := render(t, 2020, Options{})
+
+	for _, want := range []string{
+		"                                 2020\n\n",
+		"       January                 February                 March\n",
+		"        1[ 2] 3  4  5                    1  2                       1\n",
+		" 27 28 29[30]31          24 25 26[27]28 29       23 24 25[26]27 28 29\n",
+		" [02] 2001               [27] 2003               [26] 2004\n",
+		" [30] 2002\n",
+		" 28 29 30[31]\n",
+		" [03] 2013\n",
+		" [31] 2014",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, "(") {
+		t.Errorf("milestones marked although not requested:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "\n") || strings.HasSuffix(got, "\n\n") {
+		t.Errorf("want exactly one trailing newline")
+	}
+}
+
+func TestWriteMilestones(t *testing.T) {
+	t.Parallel()
+
+	got := render(t, 2020, Options{Milestones: true})
+
+	for _, want := range []string{
+		" 13 14 15(16)17 18 19    10 11 12(13)14 15 16     9 10 11(12)13 14 15\n",
+		" [02] 2001               (13) 2004 publication   (12) 2005 publication\n",
+		" (02) 2003 cut-off       [27] 2003               [26] 2004\n",
+		" (02) 2002 reception",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteANSI(t *testing.T) {
+	t.Parallel()
+
+	got := render(t, 2021, Options{ANSI: true, Milestones: true})
+
+	for _, want := range []string{
+		" 25 26 27 \x1b[7m28\x1b[0m 29 30 31",
+		" 11 12 13 \x1b[4m14\x1b[0m 15 16 17",
+		" [28] 2101",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Override changes the schedule of a single AIRAC cycle.
+type Override struct {
+	// Cycle is the overridden AIRAC cycle. Its identifier is not affected.
+	Cycle AIRAC
+
+	// Effective is the deviating effective date of the cycle. If zero, the
+	// arithmetic effective date is kept.
+	Effective time.Time
+
+	// Withdrawn marks the cycle as withdrawn, i.e. it never becomes
+	// effective and its predecessor stays effective until the next cycle.
+	Withdrawn bool
+
+	// Reason documents the override, e.g. a reference to the publication
+	// that announced it.
+	Reason string
+}
+
+// Calendar is the AIRAC schedule with overrides for exceptional cycles layered
+// on top of the arithmetic schedule. Its methods mirror the functions and
+// methods of AIRAC. The zero Calendar has no overrides and equals the
+// arithmetic schedule.
+//
+// Overrides only change effective dates; identifiers, years and ordinals of
+// cycles always follow the arithmetic schedule.
+type Calendar struct {
+	overrides map[AIRAC]Override
+}
+
+// NewCalendar returns a Calendar with the given overrides. It fails if a cycle
+// is overridden twice, or if the effective dates of the cycles that are not
+// withdrawn would not be strictly increasing.
+func NewCalendar(overrides ...Override) (*Calendar, error) {
+	c := &Calendar{overrides: make(map[AIRAC]Override, len(overrides))}
+
+	for _, o := range overrides {
+		if _, ok := c.overrides[o.Cycle]; ok {
+			return nil, fmt.Errorf("illegal override: cycle %s overridden twice", o.Cycle)
+		}
+		if !o.Effective.IsZero() {
+			o.Effective = o.Effective.UTC()
+		}
+		c.overrides[o.Cycle] = o
+	}
+
+	for _, o := range c.overrides {
+		if o.Withdrawn {
+			continue
+		}
+		eff := c.Effective(o.Cycle)
+		if p, ok := c.prev(o.Cycle); ok && !c.Effective(p).Before(eff) {
+			return nil, fmt.Errorf("illegal override: cycle %s effective %s, not after cycle %s effective %s",
+				o.Cycle, eff.Format(format), p, c.Effective(p).Format(format))
+		}
+		if n, ok := c.next(o.Cycle); ok && !eff.Before(c.Effective(n)) {
+			return nil, fmt.Errorf("illegal override: cycle %s effective %s, not before cycle %s effective %s",
+				o.Cycle, eff.Format(format), n, c.Effective(n).Format(format))
+		}
+	}
+
+	return c, nil
+}
+
+// calendarJSON is the JSON form of a Calendar.
+type calendarJSON struct {
+	Overrides []overrideJSON `json:"overrides"`
+}
+
+type overrideJSON struct {
+	Ident     string `json:"ident"`
+	Effective string `json:"effective,omitempty"`
+	Withdrawn bool   `json:"withdrawn,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// LoadCalendar reads a Calendar from an override table in JSON like:
+//
+//	{
+//	  "overrides": [
+//	    {"ident": "2103", "effective": "2021-03-26", "reason": "..."},
+//	    {"ident": "2104", "withdrawn": true, "reason": "..."}
+//	  ]
+//	}
+//
+// Effective dates are formatted as YYYY-MM-DD and are UTC.
+func LoadCalendar(r io.Reader) (*Calendar, error) {
+	var table calendarJSON
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&table); err != nil {
+		return nil, fmt.Errorf("illegal override table: %w", err)
+	}
+
+	overrides := make([]Override, 0, len(table.Overrides))
+	for _, oj := range table.Overrides {
+		a, err := FromString(oj.Ident)
+		if err != nil {
+			return nil, fmt.Errorf("illegal override table: %w", err)
+		}
+
+		o := Override{Cycle: a, Withdrawn: oj.Withdrawn, Reason: oj.Reason}
+		if oj.Effective != "" {
+			if o.Effective, err = time.Parse(format, oj.Effective); err != nil {
+				return nil, fmt.Errorf("illegal override table: cycle %s: %w", a, err)
+			}
+		}
+		if o.Withdrawn == !o.Effective.IsZero() {
+			return nil, fmt.Errorf("illegal override table: cycle %s: want either effective or withdrawn", a)
+		}
+
+		overrides = append(overrides, o)
+	}
+
+	return NewCalendar(overrides...)
+}
+
+// MarshalJSON encodes the override table in the format of LoadCalendar.
+func (c Calendar) MarshalJSON() ([]byte, error) {
+	table := calendarJSON{Overrides: []overrideJSON{}}
+	for _, o := range c.Overrides() {
+		oj := overrideJSON{Ident: o.Cycle.String(), Withdrawn: o.Withdrawn, Reason: o.Reason}
+		if !o.Effective.IsZero() {
+			oj.Effective = o.Effective.Format(format)
+		}
+		table.Overrides = append(table.Overrides, oj)
+	}
+	return json.Marshal(table)
+}
+
+// UnmarshalJSON decodes an override table in the format of LoadCalendar and
+// replaces the overrides of c with it.
+func (c *Calendar) UnmarshalJSON(b []byte) error {
+	cal, err := LoadCalendar(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	*c = *cal
+	return nil
+}
+
+// Overrides returns the overrides in chronological order of their cycles.
+func (c *Calendar) Overrides() []Override {
+	overrides := make([]Override, 0, len(c.overrides))
+	for _, o := range c.overrides {
+		overrides = append(overrides, o)
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].Cycle < overrides[j].Cycle })
+	return overrides
+}
+
+// Override returns the override of cycle a, if any.
+func (c *Calendar) Override(a AIRAC) (Override, bool) {
+	o, ok := c.overrides[a]
+	return o, ok
+}
+
+// Withdrawn reports whether cycle a is withdrawn.
+func (c *Calendar) Withdrawn(a AIRAC) bool {
+	return c.overrides[a].Withdrawn
+}
+
+// Effective returns the effective date of cycle a, which is the arithmetic
+// date unless overridden. A withdrawn cycle keeps its date although it never
+// becomes effective.
+func (c *Calendar) Effective(a AIRAC) time.Time {
+	if o, ok := c.overrides[a]; ok && !o.Effective.IsZero() {
+		return o.Effective
+	}
+	return a.Effective()
+}
+
+// EffectivePrecise returns the instant cycle a becomes effective, i.e. 00:01
+// UTC on its effective date.
+func (c *Calendar) EffectivePrecise(a AIRAC) time.Time {
+	return c.Effective(a).Add(PreciseOffset)
+}
+
+// Next returns the first cycle after a that is not withdrawn.
+func (c *Calendar) Next(a AIRAC) AIRAC {
+	n, _ := c.next(a)
+	return n
+}
+
+// Previous returns the last cycle before a that is not withdrawn.
+func (c *Calendar) Previous(a AIRAC) AIRAC {
+	p, _ := c.prev(a)
+	return p
+}
+
+// Expires returns the date the next cycle that is not withdrawn becomes
+// effective, which ends cycle a.
+func (c *Calendar) Expires(a AIRAC) time.Time {
+	return c.Effective(c.Next(a))
+}
+
+// FromDate returns the AIRAC cycle that is effective at date, skipping
+// withdrawn cycles.
+func (c *Calendar) FromDate(date time.Time) AIRAC {
+	a := FromDate(date)
+	for c.Withdrawn(a) || c.Effective(a).After(date) {
+		p, ok := c.prev(a)
+		if !ok {
+			return a
+		}
+		a = p
+	}
+	for {
+		n, ok := c.next(a)
+		if !ok || c.Effective(n).After(date) {
+			return a
+		}
+		a = n
+	}
+}
+
+// FromDatePrecise returns the AIRAC cycle that is effective at the instant t,
+// with cycles changing at 00:01 UTC rather than at midnight.
+func (c *Calendar) FromDatePrecise(t time.Time) AIRAC {
+	return c.FromDate(t.Add(-PreciseOffset))
+}
+
+// Milestone returns the date of milestone m of cycle a, relative to its
+// effective date.
+func (c *Calendar) Milestone(a AIRAC, m Milestone) time.Time {
+	return c.Effective(a).Add(m.Offset())
+}
+
+// LongString returns a verbose representation of cycle a like
+// AIRAC.LongString, with the dates of the Calendar.
+func (c *Calendar) LongString(a AIRAC) string {
+	return fmt.Sprintf("%s (effective: %s; expires: %s)",
+		a, c.Effective(a).Format(format), c.Expires(a).Add(-1).Format(format))
+}
+
+// Info returns the structured form of cycle a like AIRAC.Info, with the dates
+// of the Calendar.
+func (c *Calendar) Info(a AIRAC) Info {
+	info := Info{
+		Ident:     a.String(),
+		Year:      a.Year(),
+		Ordinal:   a.Ordinal(),
+		Effective: c.Effective(a).Format(format),
+		Expires:   c.Expires(a).Add(-1).Format(format),
+	}
+
+	for _, m := range Milestones() {
+		info.Milestones = append(info.Milestones, MilestoneInfo{Name: m.String(), Date: c.Milestone(a, m).Format(format)})
+	}
+
+	return info
+}
+
+// CyclesDuring is like the function CyclesDuring with the dates of the
+// Calendar. Withdrawn cycles are skipped.
+func (c *Calendar) CyclesDuring(start, end time.Time) []Span {
+	return c.cyclesDuring(start, end, 0)
+}
+
+// CyclesDuringPrecise is like CyclesDuring, but cycles change at 00:01 UTC
+// rather than at midnight.
+func (c *Calendar) CyclesDuringPrecise(start, end time.Time) []Span {
+	return c.cyclesDuring(start, end, PreciseOffset)
+}
+
+func (c *Calendar) cyclesDuring(start, end time.Time, offset time.Duration) []Span {
+	if end.Before(start) {
+		return nil
+	}
+
+	var spans []Span
+	for a := c.FromDate(start.Add(-offset)); ; a = c.Next(a) {
+		s := Span{Cycle: a, Start: start, End: c.Expires(a).Add(offset)}
+		if !s.End.Before(end) {
+			s.End = end
+			return append(spans, s)
+		}
+
+		spans = append(spans, s)
+		start = s.End
+	}
+}
+
+// next returns the first cycle after a that is not withdrawn.
+func (c *Calendar) next(a AIRAC) (AIRAC, bool) {
+	for n := a + 1; n > a; n++ {
+		if !c.Withdrawn(n) {
+			return n, true
+		}
+	}
+	return a, false
+}
+
+// prev returns the last cycle before a that is not withdrawn.
+func (c *Calendar) prev(a AIRAC) (AIRAC, bool) {
+	for p := a - 1; p < a; p-- {
+		if !c.Withdrawn(p) {
+			return p, true
+		}
+	}
+	return a, false
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(format, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCalendarZero(t *testing.T) {
+	t.Parallel()
+
+	var c Calendar
+	for _, s := range []string{"1998-01-29", "2020-12-31", "2021-02-03", "2021-02-24"} {
+		d := date(s)
+		if got, want := c.FromDate(d), FromDate(d); got != want {
+			t.Errorf("%s: want %s, got %s", s, want, got)
+		}
+	}
+
+	a := FromStringMust("2101")
+	if got, want := c.LongString(a), a.LongString(); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	if got, want := c.Info(a), a.Info(); got.Expires != want.Expires || got.Milestones[1] != want.Milestones[1] {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+// nolint:funlen
+func TestCalendarOverrides(t *testing.T) {
+	t.Parallel()
+
+	table := `{
+  "overrides": [
+    {"ident": "2103", "effective": "2021-03-30", "reason": "shifted"},
+    {"ident": "2105", "withdrawn": true, "reason": "withdrawn"}
+  ]
+}`
+
+	c, err := LoadCalendar(strings.NewReader(table))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testt := []struct {
+		date string
+		want string
+	}{
+		{"2021-03-24", "2102"},
+		{"2021-03-25", "2102"}, // arithmetic effective date of 2103
+		{"2021-03-29", "2102"},
+		{"2021-03-30", "2103"},
+		{"2021-04-22", "2104"},
+		{"2021-05-19", "2104"},
+		{"2021-05-20", "2104"}, // 2105 withdrawn
+		{"2021-06-16", "2104"},
+		{"2021-06-17", "2106"},
+	}
+
+	for _, tt := range testt {
+		if got := c.FromDate(date(tt.date)).String(); got != tt.want {
+			t.Errorf("%s: want %s, got %s", tt.date, tt.want, got)
+		}
+	}
+
+	a2102, a2103, a2104 := FromStringMust("2102"), FromStringMust("2103"), FromStringMust("2104")
+	if got, want := c.LongString(a2102), "2102 (effective: 2021-02-25; expires: 2021-03-29)"; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	if got, want := c.LongString(a2104), "2104 (effective: 2021-04-22; expires: 2021-06-16)"; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	if got, want := c.Milestone(a2103, Publication).Format(format), "2021-02-16"; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	if got, want := c.Next(a2104).String(), "2106"; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	if got, want := c.Previous(FromStringMust("2106")).String(), "2104"; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+	if got := c.FromDatePrecise(date("2021-03-30")); got != a2102 {
+		t.Errorf("want 2102 at midnight, got %s", got)
+	}
+	if got := c.EffectivePrecise(a2103); !got.Equal(date("2021-03-30").Add(time.Minute)) {
+		t.Errorf("want 00:01 UTC, got %s", got)
+	}
+
+	spans := c.CyclesDuring(date("2021-04-01"), date("2021-07-01"))
+	var idents []string
+	for _, s := range spans {
+		idents = append(idents, s.Cycle.String())
+	}
+	if got, want := strings.Join(idents, " "), "2103 2104 2106"; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+
+	if !c.Withdrawn(FromStringMust("2105")) || c.Withdrawn(a2104) {
+		t.Error("want only 2105 withdrawn")
+	}
+	if o, ok := c.Override(a2103); !ok || o.Reason != "shifted" {
+		t.Errorf("want override of 2103, got %v, %t", o, ok)
+	}
+}
+
+func TestCalendarJSON(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCalendar(
+		Override{Cycle: FromStringMust("2105"), Withdrawn: true},
+		Override{Cycle: FromStringMust("2103"), Effective: date("2021-03-30"), Reason: "shifted"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"overrides":[{"ident":"2103","effective":"2021-03-30","reason":"shifted"},{"ident":"2105","withdrawn":true}]}`
+	if string(b) != want {
+		t.Errorf("want %s, got %s", want, b)
+	}
+
+	c2, err := LoadCalendar(strings.NewReader(string(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(c2.Overrides()), 2; got != want {
+		t.Errorf("want %d overrides, got %d", want, got)
+	}
+
+	// by value as a struct field through encoding/json
+	type config struct{ Calendar Calendar }
+	b, err = json.Marshal(config{*c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Calendar":` + want + `}`; string(b) != want {
+		t.Errorf("want %s, got %s", want, b)
+	}
+
+	var back config
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	o, ok := back.Calendar.Override(FromStringMust("2103"))
+	if !ok || o.Reason != "shifted" || !back.Calendar.Withdrawn(FromStringMust("2105")) {
+		t.Errorf("want overrides of 2103 and 2105 to round-trip, got %v", back.Calendar.Overrides())
+	}
+
+	if err := json.Unmarshal([]byte(`{"Calendar":{"overrides":[{"ident":"2199"}]}}`), &back); err == nil {
+		t.Error("illegal override table: want error, got nil")
+	}
+}
+
+func TestCalendarErrors(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		name  string
+		table string
+	}{
+		{"not JSON", `overrides`},
+		{"unknown field", `{"overrides": [{"ident": "2103", "withdrawn": true, "comment": "x"}]}`},
+		{"illegal ident", `{"overrides": [{"ident": "2115", "withdrawn": true}]}`},
+		{"illegal date", `{"overrides": [{"ident": "2103", "effective": "30.03.2021"}]}`},
+		{"neither", `{"overrides": [{"ident": "2103"}]}`},
+		{"both", `{"overrides": [{"ident": "2103", "effective": "2021-03-30", "withdrawn": true}]}`},
+		{"twice", `{"overrides": [{"ident": "2103", "withdrawn": true}, {"ident": "2103", "withdrawn": true}]}`},
+		{"before predecessor", `{"overrides": [{"ident": "2103", "effective": "2021-02-25"}]}`},
+		{"after successor", `{"overrides": [{"ident": "2103", "effective": "2021-04-22"}]}`},
+		{"after successor of withdrawn", `{"overrides": [{"ident": "2103", "effective": "2021-05-20"}, {"ident": "2104", "withdrawn": true}]}`},
+	}
+
+	for _, tt := range testt {
+		if _, err := LoadCalendar(strings.NewReader(tt.table)); err == nil {
+			t.Errorf("%s: want error, got nil", tt.name)
+		}
+	}
+
+	// a withdrawn successor makes room for a later date
+	if _, err := LoadCalendar(strings.NewReader(`{"overrides": [{"ident": "2103", "effective": "2021-05-19"}, {"ident": "2104", "withdrawn": true}]}`)); err != nil {
+		t.Errorf("want nil, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jwkohnen/airac/aixm"
+)
+
+func aixmFlags(fs *flag.FlagSet, o *options) {
+	fs.BoolVar(&o.aixmProblems, "problems", false, "only report features with misaligned time slices")
+}
+
+// runAIXM checks the AIXM 5.1 document on stdin and reports the permanent
+// time slices per feature identifier. It exits 1 if any is not aligned to the
+// AIRAC schedule.
+func runAIXM(e env, o options, _ []string) error {
+	rep, err := aixm.Check(e.stdin)
+	if err != nil {
+		return err
+	}
+
+	var misaligned int
+	for _, f := range rep.Features {
+		if !f.OK() {
+			misaligned++
+		}
+		if o.aixmProblems && f.OK() {
+			continue
+		}
+		if err := writeFeature(e.stdout, f); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(e.stdout, "features: %d, permanent time slices: %d, features not aligned: %d\n",
+		len(rep.Features), rep.Slices(), misaligned)
+
+	if misaligned > 0 {
+		return exitStatus(exitError)
+	}
+	return nil
+}
+
+func writeFeature(w io.Writer, f aixm.Feature) error {
+	status := "ok"
+	if !f.OK() {
+		status = "NOT ALIGNED"
+	}
+	fmt.Fprintf(w, "%s %s (line %d): %s\n", f.Type, f.Identifier, f.Line, status)
+
+	for _, s := range f.Slices {
+		fmt.Fprintf(w, "  %-9s %d.%d from %s  %s (line %d)\n",
+			s.Interpretation, s.Sequence, s.Correction, s.Begin.Format(time.RFC3339), s.Cycle, s.Line)
+		for _, p := range s.Problems {
+			fmt.Fprintf(w, "    %s\n", p)
+		}
+	}
+	if f.Skipped > 0 {
+		fmt.Fprintf(w, "  TEMPDELTA or SNAPSHOT time slices not checked: %d\n", f.Skipped)
+	}
+
+	_, err := fmt.Fprintln(w)
+	return err
+}
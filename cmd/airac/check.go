@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/monitor"
+)
+
+// Exit codes of monitoring plugins (Nagios, Icinga etc.).
+const (
+	pluginOK       = 0
+	pluginWarning  = 1
+	pluginCritical = 2
+	pluginUnknown  = 3
+)
+
+type checkOptions struct {
+	cycle string
+	warn  string
+	now   string
+}
+
+func checkFlags(fs *flag.FlagSet, o *options) {
+	fs.StringVar(&o.check.cycle, "cycle", "", "`cycle` or range of cycles of the dataset, e.g. 2101 or 2101..2103")
+	fs.StringVar(&o.check.warn, "warn", "7d", "warn this many `days` before expiry, e.g. 7d")
+	fs.StringVar(&o.check.now, "now", "", "check at this `time` (RFC 3339 or YYYY-MM-DD) instead of now")
+	o.usageExit = pluginUnknown
+}
+
+// runCheck reports the currency of a dataset in the format of monitoring
+// plugins, with the days remaining as performance data, or the days until the
+// dataset becomes effective if it is not yet.
+func runCheck(e env, o options, _ []string) error {
+	dataset, warnDays, now, err := o.check.parse(e)
+	if err != nil {
+		fmt.Fprintf(e.stdout, "AIRAC UNKNOWN - %v\n", err)
+		return exitStatus(pluginUnknown)
+	}
+
+	s := monitor.Check(dataset, now, warnDays)
+	days := s.Remaining.Hours() / 24
+	// the date the next cycle takes over, not the last effective day
+	superseded := dataset.Expires().Format(dateFormat)
+
+	// Perfdata thresholds use the plugin range syntax: "7:" alerts below 7.
+	perf := fmt.Sprintf("days_remaining=%.2f;%d:;0:", days, warnDays)
+
+	var status int
+	var label, msg string
+	switch s.State {
+	case monitor.Current:
+		status, label = pluginOK, "OK"
+		msg = fmt.Sprintf("%s is current, superseded %s in %.1f days", dataset, superseded, days)
+	case monitor.ExpiringSoon:
+		status, label = pluginWarning, "WARNING"
+		msg = fmt.Sprintf("%s is superseded %s in %.1f days", dataset, superseded, days)
+	case monitor.Expired:
+		status, label = pluginCritical, "CRITICAL"
+		msg = fmt.Sprintf("%s was superseded %s, %.1f days ago; current cycle is %s", dataset, superseded, -days, airac.FromDate(now))
+	default:
+		status, label = pluginCritical, "CRITICAL"
+		until := dataset.Effective().Sub(now).Hours() / 24
+		msg = fmt.Sprintf("%s is not yet effective, effective from %s in %.1f days", dataset, dataset.Effective().Format(dateFormat), until)
+		// The days remaining would read as healthy; "0" alerts above 0.
+		perf = fmt.Sprintf("days_until_effective=%.2f;;0", until)
+	}
+
+	fmt.Fprintf(e.stdout, "AIRAC %s - %s | %s\n", label, msg, perf)
+
+	if status == pluginOK {
+		return nil
+	}
+	return exitStatus(status)
+}
+
+func (c checkOptions) parse(e env) (dataset airac.Range, warnDays int, now time.Time, err error) {
+	if c.cycle == "" {
+		return dataset, 0, now, fmt.Errorf("missing -cycle")
+	}
+
+	dataset, err = airac.ParseRange(c.cycle)
+	if err != nil {
+		return dataset, 0, now, err
+	}
+
+	warnDays, err = monitor.ParseDays(c.warn)
+	if err != nil {
+		return dataset, 0, now, fmt.Errorf("illegal -warn %q, want days, e.g. 7d", c.warn)
+	}
+
+	switch {
+	case c.now == "":
+		now = e.now()
+	case len(c.now) == len(dateFormat):
+		now, err = time.Parse(dateFormat, c.now)
+	default:
+		now, err = time.Parse(time.RFC3339, c.now)
+	}
+	if err != nil {
+		return dataset, 0, now, fmt.Errorf("illegal -now %q, want RFC 3339 or YYYY-MM-DD", c.now)
+	}
+
+	return dataset, warnDays, now, nil
+}
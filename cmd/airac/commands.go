@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/cal"
+)
+
+const dateFormat = "2006-01-02"
+
+func runCurrent(e env, o options, _ []string) error {
+	return o.printer.cycle(airac.FromDate(e.now()), false)
+}
+
+func runNext(e env, o options, _ []string) error {
+	return o.printer.cycle(airac.FromDate(e.now())+1, false)
+}
+
+func runShow(_ env, o options, args []string) error {
+	a, err := airac.FromString(args[0])
+	if err != nil {
+		return err
+	}
+	return o.printer.cycle(a, true)
+}
+
+func runDate(_ env, o options, args []string) error {
+	date, err := time.Parse(dateFormat, args[0])
+	if err != nil {
+		return fmt.Errorf("illegal date %q, want YYYY-MM-DD", args[0])
+	}
+	return o.printer.cycle(airac.FromDate(date), false)
+}
+
+func runYear(_ env, o options, args []string) error {
+	year, err := parseYear(args[0])
+	if err != nil {
+		return err
+	}
+	return o.printer.cycles(airac.CyclesInYear(year).Cycles())
+}
+
+func runRange(_ env, o options, args []string) error {
+	r, err := airac.ParseRange(args[0])
+	if err != nil {
+		return err
+	}
+	return o.printer.cycles(r.Cycles())
+}
+
+func calFlags(fs *flag.FlagSet, o *options) {
+	fs.BoolVar(&o.milestones, "milestones", false, "mark milestone dates, too")
+	fs.BoolVar(&o.ansi, "ansi", false, "highlight dates with ANSI escape sequences")
+}
+
+func runCal(e env, o options, args []string) error {
+	year, err := parseYear(args[0])
+	if err != nil {
+		return err
+	}
+	return cal.Write(e.stdout, year, cal.Options{Milestones: o.milestones, ANSI: o.ansi})
+}
+
+func parseYear(s string) (int, error) {
+	year, err := strconv.Atoi(s)
+	if err != nil || year < 1902 || year > 2192 {
+		return 0, fmt.Errorf("illegal year %q, want 1902 to 2192", s)
+	}
+	return year, nil
+}
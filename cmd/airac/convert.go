@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/jwkohnen/airac/convert"
+)
+
+// layouts collects repeated -layout flags.
+type layouts []string
+
+func (l *layouts) String() string { return fmt.Sprint([]string(*l)) }
+
+func (l *layouts) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func convertFlags(fs *flag.FlagSet, o *options) {
+	fs.IntVar(&o.convert.Column, "column", 0, "zero-based `index` of the column holding the dates")
+	fs.StringVar(&o.convert.ColumnName, "name", "", "`name` of the column holding the dates; requires -header")
+	fs.BoolVar(&o.convert.Header, "header", false, "the first row is a header")
+	fs.StringVar(&o.columns, "append", "ident", "`columns` to append: ident, effective or both")
+	fs.Var((*layouts)(&o.convert.Layouts), "layout", "Go time `layout` of the dates; may be repeated (default: RFC 3339 and ISO 8601 dates)")
+	fs.BoolVar(&o.convert.Precise, "precise", false, "cycles change at 00:01 UTC")
+	fs.Func("comma", "field `delimiter` (default \",\")", func(s string) error {
+		r, n := utf8.DecodeRuneInString(s)
+		if n == 0 || n != len(s) {
+			return fmt.Errorf("illegal delimiter %q", s)
+		}
+		o.convert.Comma = r
+		return nil
+	})
+}
+
+func runConvert(e env, o options, _ []string) error {
+	switch o.columns {
+	case "ident":
+		o.convert.Append = convert.Ident
+	case "effective":
+		o.convert.Append = convert.Effective
+	case "both":
+		o.convert.Append = convert.Both
+	default:
+		return fmt.Errorf("illegal -append %q, want ident, effective or both", o.columns)
+	}
+
+	o.convert.OnError = func(err *convert.RowError) {
+		fmt.Fprintf(e.stderr, "airac convert: %v\n", err)
+	}
+
+	res, err := convert.Convert(e.stdin, e.stdout, o.convert)
+	if err != nil {
+		return err
+	}
+	if res.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", res.Failed, res.Rows)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/coverage"
+)
+
+type coverageOptions struct {
+	period string
+	json   bool
+}
+
+func coverageFlags(fs *flag.FlagSet, o *options) {
+	fs.StringVar(&o.coverage.period, "period", "", "audit this `period`, a year or range of cycles (default: from the first to the last dataset)")
+	fs.BoolVar(&o.coverage.json, "json", false, "write the report as JSON")
+}
+
+// runCoverage audits the list of datasets on stdin, see coverage.Read for the
+// format. It exits 1 if any cycle of the period is missing.
+func runCoverage(e env, o options, _ []string) error {
+	datasets, err := coverage.Read(e.stdin)
+	if err != nil {
+		return err
+	}
+
+	var r coverage.Report
+	if o.coverage.period == "" {
+		r = coverage.Analyze(datasets)
+	} else {
+		period, err := parseYearOrRange(o.coverage.period)
+		if err != nil {
+			return err
+		}
+		r = coverage.AnalyzePeriod(datasets, period)
+	}
+
+	if o.coverage.json {
+		err = writeCoverageJSON(e.stdout, r)
+	} else {
+		err = writeCoverage(e.stdout, r)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(r.Gaps) > 0 {
+		return exitStatus(exitError)
+	}
+	return nil
+}
+
+func writeCoverage(w io.Writer, r coverage.Report) error {
+	fmt.Fprintf(w, "datasets: %d, period: %s (%s), covered: %d (%.1f%%), missing: %d\n",
+		len(r.Datasets), r.Period, cycles(r.Period.Len()), r.Covered.Len(), 100*r.Ratio(), r.Missing())
+
+	if len(r.Gaps) > 0 {
+		fmt.Fprintln(w, "gaps:")
+		for _, g := range r.Gaps {
+			fmt.Fprintf(w, "  %-9s  %s to %s  %s\n",
+				g, g.Effective().Format(dateFormat), g.Expires().AddDate(0, 0, -1).Format(dateFormat), cycles(g.Len()))
+		}
+	}
+
+	if len(r.Overlaps) > 0 {
+		fmt.Fprintln(w, "overlaps:")
+		for _, o := range r.Overlaps {
+			fmt.Fprintf(w, "  %-9s  %s\n", o.Cycles, strings.Join(o.Datasets, ", "))
+		}
+	}
+
+	if r.Longest.Empty() {
+		_, err := fmt.Fprintln(w, "longest run: none")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "longest run: %s (%s)\n", r.Longest, cycles(r.Longest.Len()))
+	return err
+}
+
+func writeCoverageJSON(w io.Writer, r coverage.Report) error {
+	type overlap struct {
+		Cycles   string   `json:"cycles"`
+		Datasets []string `json:"datasets"`
+	}
+	type dataset struct {
+		Name   string `json:"name"`
+		Cycles string `json:"cycles"`
+	}
+
+	datasets := make([]dataset, len(r.Datasets))
+	for i, d := range r.Datasets {
+		datasets[i] = dataset{d.Name, d.Cycles.String()}
+	}
+	overlaps := make([]overlap, len(r.Overlaps))
+	for i, o := range r.Overlaps {
+		overlaps[i] = overlap{o.Cycles.String(), o.Datasets}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		OK       bool      `json:"ok"`
+		Period   string    `json:"period"`
+		Covered  string    `json:"covered"`
+		Gaps     string    `json:"gaps"`
+		Missing  int       `json:"missing"`
+		Ratio    float64   `json:"ratio"`
+		Longest  string    `json:"longest"`
+		Overlaps []overlap `json:"overlaps"`
+		Datasets []dataset `json:"datasets"`
+	}{
+		OK:       r.OK(),
+		Period:   r.Period.String(),
+		Covered:  r.Covered.String(),
+		Gaps:     airac.SetFromRanges(r.Gaps...).String(),
+		Missing:  r.Missing(),
+		Ratio:    r.Ratio(),
+		Longest:  r.Longest.String(),
+		Overlaps: overlaps,
+		Datasets: datasets,
+	})
+}
+
+// cycles returns n with the noun "cycle" or "cycles".
+func cycles(n int) string {
+	if n == 1 {
+		return "1 cycle"
+	}
+	return fmt.Sprintf("%d cycles", n)
+}
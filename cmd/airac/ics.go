@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/ics"
+)
+
+func icsFlags(fs *flag.FlagSet, o *options) {
+	fs.BoolVar(&o.ics.Milestones, "milestones", false, "add events for the milestones, too")
+	fs.StringVar(&o.ics.Name, "name", "", "display `name` of the calendar")
+	fs.StringVar(&o.ics.Domain, "domain", ics.DefaultDomain, "`domain` part of the event UIDs")
+	fs.Func("alarm", "add a reminder this long before each event, e.g. 7d or 12h; may be repeated", func(s string) error {
+		d, err := ics.ParseAlarm(s)
+		if err != nil {
+			return err
+		}
+		o.ics.Alarms = append(o.ics.Alarms, d)
+		return nil
+	})
+}
+
+// runICS writes an iCalendar file of a year or a range of cycles. A plain
+// four-digit argument is a year, not a cycle.
+func runICS(e env, o options, args []string) error {
+	r, err := parseYearOrRange(args[0])
+	if err != nil {
+		return err
+	}
+
+	o.ics.Stamp = e.now()
+
+	return ics.Write(e.stdout, r, o.ics)
+}
+
+// parseYearOrRange parses a year, i.e. a plain four-digit number, or a range of
+// cycles.
+func parseYearOrRange(s string) (airac.Range, error) {
+	if len(s) == len("2006") && !strings.ContainsAny(s, ".-") {
+		year, err := parseYear(s)
+		if err != nil {
+			return airac.Range{}, err
+		}
+		return airac.CyclesInYear(year), nil
+	}
+	return airac.ParseRange(s)
+}
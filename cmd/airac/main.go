@@ -0,0 +1,235 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command airac prints AIRAC cycles and their dates.
+//
+// Usage:
+//
+//	airac <command> [flags] [argument]
+//
+// The commands are:
+//
+//	current             the cycle effective today
+//	next                the cycle following the current one
+//	show <yyoo>         a cycle and its milestones, e.g. "show 2101"
+//	date <yyyy-mm-dd>   the cycle effective at a date, e.g. "date 2021-02-03"
+//	year <yyyy>         all cycles of a year, e.g. "year 2020"
+//	range <yyoo..yyoo>  a range of cycles, e.g. "range 2101..2106"
+//	cal <yyyy>          a calendar of a year with effective dates highlighted
+//	convert             append cycle columns to CSV read from stdin
+//	check               check the currency of a dataset, e.g.
+//	                    "check -cycle 2101 -warn 7d"; exits 0, 1 or 2 for
+//	                    OK, WARNING or CRITICAL like a monitoring plugin
+//	ics <yyyy|range>    an iCalendar file of a year or range of cycles for
+//	                    Outlook, Thunderbird etc., e.g. "ics -alarm 7d 2021";
+//	                    four digits are a year, use "2101..2101" for a cycle
+//	schedule <years>    a table of effective dates per year like ICAO DOC 8126
+//	                    Table 2-1, e.g. "schedule -format markdown 2021..2025";
+//	                    -format text, markdown, html or csv
+//	verify              verify the computed effective dates against the
+//	                    published ICAO and EUROCONTROL schedules; exits 1 on
+//	                    any divergence
+//	aixm                check that the BASELINE and PERMDELTA time slices of
+//	                    the AIXM 5.1 document on stdin begin on AIRAC
+//	                    effective dates; exits 1 if any does not
+//	coverage            report the gaps, overlaps and longest run of cycles of
+//	                    the datasets listed on stdin, one "<cycles> [name]"
+//	                    per line, e.g. "coverage -period 2021"; exits 1 on
+//	                    any gap
+//
+// Other commands that print cycles accept -format text, json or csv. Run
+// "airac <command> -h" for the flags of a command.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jwkohnen/airac/convert"
+	"github.com/jwkohnen/airac/ics"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// errUsage signals that usage information has already been printed.
+var errUsage = errors.New("usage")
+
+// exitStatus is returned by commands that report errors themselves and exit
+// with a specific code.
+type exitStatus int
+
+func (s exitStatus) Error() string { return fmt.Sprintf("exit status %d", int(s)) }
+
+// env is the environment a command runs in.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	now    func() time.Time
+}
+
+// options holds the flags of all commands. Each command registers the flags
+// it uses.
+type options struct {
+	format     string
+	printer    printer
+	milestones bool
+	ansi       bool
+	convert    convert.Options
+	columns    string
+	check      checkOptions
+	ics        ics.Options
+	schedule   scheduleOptions
+	verify     verifyOptions
+	coverage   coverageOptions
+
+	aixmProblems bool
+
+	// usageExit overrides the exit code on usage errors.
+	usageExit int
+}
+
+type command struct {
+	name  string
+	args  string
+	usage string
+	flags func(fs *flag.FlagSet, o *options)
+	run   func(e env, o options, args []string) error
+}
+
+// nolint:gochecknoglobals
+var commands = []command{
+	{"current", "", "print the cycle effective today", formatFlag, runCurrent},
+	{"next", "", "print the cycle following the current one", formatFlag, runNext},
+	{"show", "<yyoo>", "print a cycle and its milestones", formatFlag, runShow},
+	{"date", "<yyyy-mm-dd>", "print the cycle effective at a date", formatFlag, runDate},
+	{"year", "<yyyy>", "print all cycles of a year", formatFlag, runYear},
+	{"range", "<yyoo..yyoo>", "print a range of cycles", formatFlag, runRange},
+	{"cal", "<yyyy>", "print a calendar of a year", calFlags, runCal},
+	{"convert", "", "append cycle columns to CSV from stdin", convertFlags, runConvert},
+	{"check", "", "check the currency of a dataset (monitoring plugin)", checkFlags, runCheck},
+	{"ics", "<yyyy|range>", "write an iCalendar file of a year or range of cycles", icsFlags, runICS},
+	{"schedule", "<years>", "print a table of effective dates of a span of years", scheduleFlags, runSchedule},
+	{"verify", "", "verify computed dates against published schedules", verifyFlags, runVerify},
+	{"aixm", "", "check AIXM 5.1 time slices from stdin against the schedule", aixmFlags, runAIXM},
+	{"coverage", "", "report gaps and overlaps of a list of datasets from stdin", coverageFlags, runCoverage},
+}
+
+func formatFlag(fs *flag.FlagSet, o *options) {
+	fs.StringVar(&o.format, "format", "text", "output `format`: text, json or csv")
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, now: time.Now}))
+}
+
+func run(args []string, e env) int {
+	if len(args) == 0 {
+		usage(e.stderr)
+		return exitUsage
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		err := runCommand(cmd, e, args[1:])
+
+		var status exitStatus
+		switch {
+		case err == nil:
+			return exitOK
+		case errors.As(err, &status):
+			return int(status)
+		case errors.Is(err, errUsage):
+			return exitUsage
+		default:
+			fmt.Fprintf(e.stderr, "airac %s: %v\n", cmd.name, err)
+			return exitError
+		}
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		usage(e.stdout)
+		return exitOK
+	}
+
+	fmt.Fprintf(e.stderr, "airac: unknown command %q\n", args[0])
+	usage(e.stderr)
+
+	return exitUsage
+}
+
+func runCommand(cmd command, e env, args []string) error {
+	fs := flag.NewFlagSet("airac "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: %s\n\n%s\n\n", strings.TrimSpace("airac "+cmd.name+" [flags] "+cmd.args), cmd.usage)
+		fs.PrintDefaults()
+	}
+
+	var o options
+	cmd.flags(fs, &o)
+
+	errUsage := error(errUsage)
+	if o.usageExit != 0 {
+		errUsage = exitStatus(o.usageExit)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	wantArgs := 0
+	if cmd.args != "" {
+		wantArgs = 1
+	}
+	if fs.NArg() != wantArgs {
+		fs.Usage()
+		return errUsage
+	}
+
+	if o.format != "" {
+		p, err := newPrinter(e.stdout, o.format)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			fs.Usage()
+			return errUsage
+		}
+		o.printer = p
+	}
+
+	return cmd.run(e, o, fs.Args())
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: airac <command> [flags] [argument]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-20s %s\n", cmd.name+" "+cmd.args, cmd.usage)
+	}
+}
@@ -0,0 +1,496 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func runTest(t *testing.T, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	e := env{
+		stdout: &out,
+		stderr: &errOut,
+		now:    func() time.Time { return time.Date(2021, time.February, 3, 12, 0, 0, 0, time.UTC) },
+	}
+	code = run(args, e)
+
+	return out.String(), errOut.String(), code
+}
+
+// nolint:funlen
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		args []string
+		want string
+	}{
+		{
+			[]string{"current"},
+			"2101 (effective: 2021-01-28; expires: 2021-02-24)\n",
+		},
+		{
+			[]string{"next"},
+			"2102 (effective: 2021-02-25; expires: 2021-03-24)\n",
+		},
+		{
+			[]string{"date", "2021-02-03"},
+			"2101 (effective: 2021-01-28; expires: 2021-02-24)\n",
+		},
+		{
+			[]string{"show", "2101"},
+			"2101 (effective: 2021-01-28; expires: 2021-02-24)\n" +
+				"  submission cut-off: 2020-12-03\n" +
+				"  publication:        2020-12-17\n" +
+				"  reception:          2020-12-31\n",
+		},
+		{
+			[]string{"range", "2013..2101"},
+			"2013 (effective: 2020-12-03; expires: 2020-12-30)\n" +
+				"2014 (effective: 2020-12-31; expires: 2021-01-27) [14th cycle]\n" +
+				"2101 (effective: 2021-01-28; expires: 2021-02-24)\n",
+		},
+		{
+			[]string{"show", "-format", "csv", "2101"},
+			"ident,year,ordinal,effective,expires,submission cut-off,publication,reception\n" +
+				"2101,2021,1,2021-01-28,2021-02-24,2020-12-03,2020-12-17,2020-12-31\n",
+		},
+		{
+			[]string{"current", "-format", "json"},
+			`{
+  "ident": "2101",
+  "year": 2021,
+  "ordinal": 1,
+  "effective": "2021-01-28",
+  "expires": "2021-02-24",
+  "milestones": [
+    {
+      "name": "submission cut-off",
+      "date": "2020-12-03"
+    },
+    {
+      "name": "publication",
+      "date": "2020-12-17"
+    },
+    {
+      "name": "reception",
+      "date": "2020-12-31"
+    }
+  ]
+}
+`,
+		},
+	}
+
+	for _, tt := range testt {
+		tt := tt
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			t.Parallel()
+
+			stdout, stderr, code := runTest(t, tt.args...)
+			if code != exitOK {
+				t.Fatalf("want exit code %d, got %d: %s", exitOK, code, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("want:\n%s\ngot:\n%s", tt.want, stdout)
+			}
+		})
+	}
+}
+
+func TestRunYear(t *testing.T) {
+	t.Parallel()
+
+	stdout, _, code := runTest(t, "year", "-format", "csv", "2020")
+	if code != exitOK {
+		t.Fatalf("want exit code %d, got %d", exitOK, code)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 15 {
+		t.Fatalf("want header and 14 cycles, got %d lines", len(lines))
+	}
+	if !strings.HasPrefix(lines[14], "2014,2020,14,2020-12-31,") {
+		t.Errorf("wrong 14th cycle: %s", lines[14])
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		args []string
+		code int
+	}{
+		{nil, exitUsage},
+		{[]string{"nope"}, exitUsage},
+		{[]string{"show"}, exitUsage},
+		{[]string{"show", "2101", "2102"}, exitUsage},
+		{[]string{"current", "-format", "xml"}, exitUsage},
+		{[]string{"show", "2115"}, exitError},
+		{[]string{"date", "03.02.2021"}, exitError},
+		{[]string{"year", "20x0"}, exitError},
+		{[]string{"range", "2106..2101"}, exitError},
+	}
+
+	for _, tt := range testt {
+		if _, stderr, code := runTest(t, tt.args...); code != tt.code {
+			t.Errorf("%q: want exit code %d, got %d: %s", tt.args, tt.code, code, stderr)
+		}
+	}
+}
+
+func TestRunCal(t *testing.T) {
+	t.Parallel()
+
+	stdout, stderr, code := runTest(t, "cal", "-milestones", "2021")
+	if code != exitOK {
+		t.Fatalf("want exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+
+	for _, want := range []string{" 25 26 27[28]29 30 31", " [28] 2101", "(14) 2102 publication"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("want %q in:\n%s", want, stdout)
+		}
+	}
+
+	if _, _, code := runTest(t, "cal", "-format", "json", "2021"); code != exitUsage {
+		t.Errorf("cal does not support -format: want exit code %d, got %d", exitUsage, code)
+	}
+}
+
+func TestRunConvert(t *testing.T) {
+	t.Parallel()
+
+	var out, errOut bytes.Buffer
+	e := env{
+		stdin:  strings.NewReader("id;date\n1;2021-02-03\n2;nope\n"),
+		stdout: &out,
+		stderr: &errOut,
+		now:    time.Now,
+	}
+
+	code := run([]string{"convert", "-header", "-name", "date", "-comma", ";", "-append", "both"}, e)
+	if code != exitError {
+		t.Errorf("want exit code %d, got %d", exitError, code)
+	}
+
+	if want := "id;date;airac;airac_effective\n1;2021-02-03;2101;2021-01-28\n2;nope;;\n"; out.String() != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, out.String())
+	}
+
+	wantErr := "airac convert: line 3: illegal date \"nope\"\nairac convert: 1 of 2 rows failed\n"
+	if errOut.String() != wantErr {
+		t.Errorf("want:\n%s\ngot:\n%s", wantErr, errOut.String())
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		args []string
+		code int
+		want string
+	}{
+		{
+			[]string{"-cycle", "2101", "-warn", "7d", "-now", "2021-02-03"},
+			pluginOK,
+			"AIRAC OK - 2101 is current, superseded 2021-02-25 in 22.0 days | days_remaining=22.00;7:;0:\n",
+		},
+		{
+			[]string{"-cycle", "2101", "-warn", "7d", "-now", "2021-02-20T12:00:00Z"},
+			pluginWarning,
+			"AIRAC WARNING - 2101 is superseded 2021-02-25 in 4.5 days | days_remaining=4.50;7:;0:\n",
+		},
+		{
+			[]string{"-cycle", "2101..2102", "-warn", "7", "-now", "2021-02-20"},
+			pluginOK,
+			"AIRAC OK - 2101-2102 is current, superseded 2021-03-25 in 33.0 days | days_remaining=33.00;7:;0:\n",
+		},
+		{
+			[]string{"-cycle", "2101", "-now", "2021-03-01"},
+			pluginCritical,
+			"AIRAC CRITICAL - 2101 was superseded 2021-02-25, 4.0 days ago; current cycle is 2102 | days_remaining=-4.00;7:;0:\n",
+		},
+		{
+			[]string{"-cycle", "2102"},
+			pluginCritical,
+			"AIRAC CRITICAL - 2102 is not yet effective, effective from 2021-02-25 in 21.5 days | days_until_effective=21.50;;0\n",
+		},
+		{
+			[]string{"-cycle", "2115"},
+			pluginUnknown,
+			"AIRAC UNKNOWN - illegal AIRAC range \"2115\": illegal AIRAC id \"2115\"\n",
+		},
+		{
+			[]string{"-cycle", "2101", "-warn", "a week"},
+			pluginUnknown,
+			"AIRAC UNKNOWN - illegal -warn \"a week\", want days, e.g. 7d\n",
+		},
+		{
+			[]string{"-cycle", "2101", "-warn", "12h"},
+			pluginUnknown,
+			"AIRAC UNKNOWN - illegal -warn \"12h\", want days, e.g. 7d\n",
+		},
+		{
+			[]string{},
+			pluginUnknown,
+			"AIRAC UNKNOWN - missing -cycle\n",
+		},
+		{
+			[]string{"-bogus"},
+			pluginUnknown,
+			"",
+		},
+	}
+
+	for _, tt := range testt {
+		stdout, _, code := runTest(t, append([]string{"check"}, tt.args...)...)
+		if code != tt.code {
+			t.Errorf("%q: want exit code %d, got %d", tt.args, tt.code, code)
+		}
+		if stdout != tt.want {
+			t.Errorf("%q: want %q, got %q", tt.args, tt.want, stdout)
+		}
+	}
+}
+
+func TestRunICS(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		args   []string
+		events int
+		alarms int
+		want   string
+	}{
+		{[]string{"2021"}, 13, 0, "UID:2101-effective@airac.jwkohnen.github.com\r\n"},
+		{[]string{"2101..2101"}, 1, 0, "DTSTART;VALUE=DATE:20210128\r\n"},
+		{[]string{"-milestones", "-alarm", "7d", "-alarm", "12h", "2101-2102"}, 8, 16, "TRIGGER:-PT12H\r\n"},
+		{[]string{"-domain", "example.com", "2101..2101"}, 1, 0, "UID:2101-effective@example.com\r\n"},
+	}
+
+	for _, tt := range testt {
+		stdout, stderr, code := runTest(t, append([]string{"ics"}, tt.args...)...)
+		if code != exitOK {
+			t.Errorf("%v: want exit code %d, got %d: %s", tt.args, exitOK, code, stderr)
+			continue
+		}
+		if got := strings.Count(stdout, "BEGIN:VEVENT"); got != tt.events {
+			t.Errorf("%v: want %d events, got %d", tt.args, tt.events, got)
+		}
+		if got := strings.Count(stdout, "BEGIN:VALARM"); got != tt.alarms {
+			t.Errorf("%v: want %d alarms, got %d", tt.args, tt.alarms, got)
+		}
+		if !strings.Contains(stdout, tt.want) {
+			t.Errorf("%v: want %q in\n%s", tt.args, tt.want, stdout)
+		}
+		if !strings.Contains(stdout, "DTSTAMP:20210203T120000Z\r\n") {
+			t.Errorf("%v: want DTSTAMP of now in\n%s", tt.args, stdout)
+		}
+	}
+
+	for _, args := range [][]string{{"ics", "1900"}, {"ics", "2115..2116"}, {"ics", "-alarm", "soon", "2021"}} {
+		if _, _, code := runTest(t, args...); code == exitOK {
+			t.Errorf("%v: want failure, got exit code %d", args, code)
+		}
+	}
+}
+
+func TestRunSchedule(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"2021"}, "Schedule of AIRAC effective dates, 2021\n\n #  2021\n--  ------\n 1  28 Jan\n"},
+		{[]string{"-format", "csv", "2019..2020"}, "#,2019,2020\n1,3 Jan,2 Jan\n"},
+		{[]string{"-format", "csv", "-layout", "02/01", "-milestones", "2021"}, "1,28/01,03/12 2020,17/12 2020,31/12 2020\n"},
+		{[]string{"-format", "markdown", "-title", "AIRAC", "2021"}, "**AIRAC**\n\n| # | 2021 |\n"},
+		{[]string{"-format", "html", "2021"}, "<table>\n<caption>Schedule of AIRAC effective dates, 2021</caption>\n"},
+	}
+
+	for _, tt := range testt {
+		stdout, stderr, code := runTest(t, append([]string{"schedule"}, tt.args...)...)
+		if code != exitOK {
+			t.Errorf("%v: want exit code %d, got %d: %s", tt.args, exitOK, code, stderr)
+		}
+		if !strings.Contains(stdout, tt.want) {
+			t.Errorf("%v: want %q in\n%s", tt.args, tt.want, stdout)
+		}
+	}
+
+	for _, args := range [][]string{{"schedule", "2022..2021"}, {"schedule", "-format", "pdf", "2021"}, {"schedule", "21"}} {
+		if _, _, code := runTest(t, args...); code != exitError {
+			t.Errorf("%v: want exit code %d, got %d", args, exitError, code)
+		}
+	}
+}
+
+func TestRunVerify(t *testing.T) {
+	t.Parallel()
+
+	stdout, stderr, code := runTest(t, "verify")
+	if code != exitOK {
+		t.Fatalf("want exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	for _, want := range []string{"328 published dates from 4 sources:\n", "erratum   1406 published 2014-05-20, computed 2014-05-29;", "\nOK: all 326 dates match, 2 known errata\n"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("want %q in\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "ok        0301") {
+		t.Errorf("want matching dates only with -v, got\n%s", stdout)
+	}
+
+	stdout, _, _ = runTest(t, "verify", "-v")
+	if want := "ok        0301 published 2003-01-23, computed 2003-01-23; icao-8126-table-2-1, column 2003, row 1, printed \"23 January\"\n"; !strings.Contains(stdout, want) {
+		t.Errorf("want %q in\n%s", want, stdout)
+	}
+
+	stdout, _, _ = runTest(t, "verify", "-json")
+	if !strings.HasPrefix(stdout, "{\n  \"ok\": true,\n  \"sources\": [") {
+		t.Errorf("want JSON report, got\n%.200s", stdout)
+	}
+}
+
+func TestRunAIXM(t *testing.T) {
+	t.Parallel()
+
+	message, err := os.ReadFile("../../aixm/testdata/message.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testt := []struct {
+		args []string
+		want []string
+		not  string
+	}{
+		{
+			[]string{"aixm"},
+			[]string{
+				"AirportHeliport dd062d88-3e64-4a5d-bebd-89476db9ebea (line 7): ok\n  BASELINE  1.0 from 2021-01-28T00:00:00Z  2101 (line 9)\n",
+				"Navaid 1b54b2d6-a5ff-4e57-94c2-f4047a381c64 (line 64): NOT ALIGNED\n",
+				"    validTime begin 2021-03-01T00:00:00Z is not on an AIRAC effective date",
+				"features: 2, permanent time slices: 3, features not aligned: 1\n",
+			},
+			"",
+		},
+		{
+			[]string{"aixm", "-problems"},
+			[]string{"Navaid 1b54b2d6-a5ff-4e57-94c2-f4047a381c64 (line 64): NOT ALIGNED\n"},
+			"AirportHeliport",
+		},
+	}
+
+	for _, tt := range testt {
+		var out, errOut bytes.Buffer
+		e := env{stdin: bytes.NewReader(message), stdout: &out, stderr: &errOut, now: time.Now}
+
+		if code := run(tt.args, e); code != exitError {
+			t.Errorf("%v: want exit code %d, got %d: %s", tt.args, exitError, code, errOut.String())
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%v: want %q in\n%s", tt.args, want, out.String())
+			}
+		}
+		if tt.not != "" && strings.Contains(out.String(), tt.not) {
+			t.Errorf("%v: want no %q in\n%s", tt.args, tt.not, out.String())
+		}
+	}
+}
+
+func TestRunCoverage(t *testing.T) {
+	t.Parallel()
+
+	const list = "2101 nav-2101.zip\n2102..2104 nav-2102-2104.zip\n2104 fix.zip\n2108-2110 nav-2108.zip\n"
+
+	testt := []struct {
+		args []string
+		code int
+		want []string
+	}{
+		{
+			[]string{"coverage"},
+			exitError,
+			[]string{
+				"datasets: 4, period: 2101-2110 (10 cycles), covered: 7 (70.0%), missing: 3\n",
+				"gaps:\n  2105-2107  2021-05-20 to 2021-08-11  3 cycles\n",
+				"overlaps:\n  2104       nav-2102-2104.zip, fix.zip\n",
+				"longest run: 2101-2104 (4 cycles)\n",
+			},
+		},
+		{
+			[]string{"coverage", "-period", "2102..2104"},
+			exitOK,
+			[]string{"period: 2102-2104 (3 cycles), covered: 3 (100.0%), missing: 0\n"},
+		},
+		{
+			[]string{"coverage", "-period", "2021", "-json"},
+			exitError,
+			[]string{`"period": "2101-2113"`, `"gaps": "2105-2107,2111-2113"`},
+		},
+	}
+
+	for _, tt := range testt {
+		var out, errOut bytes.Buffer
+		e := env{stdin: strings.NewReader(list), stdout: &out, stderr: &errOut, now: time.Now}
+
+		if code := run(tt.args, e); code != tt.code {
+			t.Errorf("%v: want exit code %d, got %d: %s", tt.args, tt.code, code, errOut.String())
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%v: want %q in\n%s", tt.args, want, out.String())
+			}
+		}
+	}
+}
+
+func TestRunAIXMGroupsByIdentifier(t *testing.T) {
+	t.Parallel()
+
+	members, err := os.ReadFile("../../aixm/testdata/members.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out, errOut bytes.Buffer
+	e := env{stdin: bytes.NewReader(members), stdout: &out, stderr: &errOut, now: time.Now}
+
+	if code := run([]string{"aixm"}, e); code != exitError {
+		t.Errorf("want exit code %d, got %d: %s", exitError, code, errOut.String())
+	}
+	want := "features: 2, permanent time slices: 3, features not aligned: 1\n"
+	if !strings.HasSuffix(out.String(), want) {
+		t.Errorf("want %q at the end of\n%s", want, out.String())
+	}
+	if n := strings.Count(out.String(), "5b2a3f4e-0c1d-4e7f-9a8b-1c2d3e4f5a6b"); n != 1 {
+		t.Errorf("want the runway reported once, got %d times", n)
+	}
+}
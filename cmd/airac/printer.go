@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jwkohnen/airac"
+)
+
+// printer writes cycles in one of the output formats.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (printer, error) {
+	switch format {
+	case "text", "json", "csv":
+		return printer{w: w, format: format}, nil
+	default:
+		return printer{}, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// cycle writes a single cycle. In text format milestones are only written if
+// requested.
+func (p printer) cycle(a airac.AIRAC, milestones bool) error {
+	switch p.format {
+	case "json":
+		return p.json(a.Info())
+	case "csv":
+		return p.csv([]airac.AIRAC{a})
+	default:
+		if _, err := fmt.Fprintln(p.w, text(a)); err != nil {
+			return err
+		}
+		if !milestones {
+			return nil
+		}
+		for _, m := range airac.Milestones() {
+			if _, err := fmt.Fprintf(p.w, "  %-19s %s\n", m.String()+":", a.Milestone(m).Format(dateFormat)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// cycles writes a list of cycles.
+func (p printer) cycles(cycles []airac.AIRAC) error {
+	switch p.format {
+	case "json":
+		infos := make([]airac.Info, 0, len(cycles))
+		for _, a := range cycles {
+			infos = append(infos, a.Info())
+		}
+		return p.json(infos)
+	case "csv":
+		return p.csv(cycles)
+	default:
+		for _, a := range cycles {
+			if _, err := fmt.Fprintln(p.w, text(a)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (p printer) json(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (p printer) csv(cycles []airac.AIRAC) error {
+	w := csv.NewWriter(p.w)
+
+	header := []string{"ident", "year", "ordinal", "effective", "expires"}
+	for _, m := range airac.Milestones() {
+		header = append(header, m.String())
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, a := range cycles {
+		info := a.Info()
+		record := []string{info.Ident, strconv.Itoa(info.Year), strconv.Itoa(info.Ordinal), info.Effective, info.Expires}
+		for _, m := range info.Milestones {
+			record = append(record, m.Date)
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// text returns the long form of a cycle and flags the rare 14th cycle of a
+// year.
+func text(a airac.AIRAC) string {
+	if a.Ordinal() == 14 {
+		return a.LongString() + " [14th cycle]"
+	}
+	return a.LongString()
+}
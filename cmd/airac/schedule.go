@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/jwkohnen/airac/schedule"
+)
+
+type scheduleOptions struct {
+	format string
+	opts   schedule.Options
+}
+
+func scheduleFlags(fs *flag.FlagSet, o *options) {
+	fs.StringVar(&o.schedule.format, "format", "text", "output `format`: text, markdown, html or csv")
+	fs.BoolVar(&o.schedule.opts.Milestones, "milestones", false, "add columns for the milestones")
+	fs.StringVar(&o.schedule.opts.Layout, "layout", schedule.DefaultLayout, "Go time `layout` of the dates, e.g. \"2 January\"")
+	fs.StringVar(&o.schedule.opts.Title, "title", "", "`title` of the table (default \"Schedule of AIRAC effective dates, <years>\")")
+}
+
+// runSchedule writes a schedule table of a year or a span of years like
+// 2021..2025.
+func runSchedule(e env, o options, args []string) error {
+	f, err := schedule.ParseFormat(o.schedule.format)
+	if err != nil {
+		return err
+	}
+	o.schedule.opts.Format = f
+
+	from, to, isSpan := strings.Cut(args[0], "..")
+	first, err := parseYear(from)
+	if err != nil {
+		return err
+	}
+	last := first
+	if isSpan {
+		if last, err = parseYear(to); err != nil {
+			return err
+		}
+		if last < first {
+			return fmt.Errorf("illegal span of years %q, %d is before %d", args[0], last, first)
+		}
+	}
+
+	return schedule.Write(e.stdout, first, last, o.schedule.opts)
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/jwkohnen/airac/reference"
+)
+
+type verifyOptions struct {
+	verbose bool
+	json    bool
+}
+
+func verifyFlags(fs *flag.FlagSet, o *options) {
+	fs.BoolVar(&o.verify.verbose, "v", false, "list every published date")
+	fs.BoolVar(&o.verify.json, "json", false, "write the report as JSON")
+}
+
+// runVerify compares the computed effective dates with the embedded reference
+// dataset of published schedules. It exits 1 if any date diverges.
+func runVerify(e env, o options, _ []string) error {
+	d, r, err := reference.Verify()
+	if err != nil {
+		return err
+	}
+
+	if o.verify.json {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(struct {
+			OK      bool               `json:"ok"`
+			Sources []reference.Source `json:"sources"`
+			Results []reference.Result `json:"results"`
+		}{r.OK(), d.Sources, r.Results})
+	} else {
+		err = writeReport(e.stdout, d, r, o.verify.verbose)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !r.OK() {
+		return exitStatus(exitError)
+	}
+	return nil
+}
+
+func writeReport(w io.Writer, d reference.Dataset, r reference.Report, verbose bool) error {
+	count := make(map[string]int)
+	for _, res := range r.Results {
+		count[res.Source]++
+	}
+
+	fmt.Fprintf(w, "%d published dates from %d sources:\n", len(r.Results), len(d.Sources))
+	for _, s := range d.Sources {
+		fmt.Fprintf(w, "  %-27s %3d  %s, %s", s.ID, count[s.ID], s.Publisher, s.Title)
+		if s.Edition != "" {
+			fmt.Fprintf(w, ", %s", s.Edition)
+		}
+		if s.Retrieved != "" {
+			fmt.Fprintf(w, ", retrieved %s", s.Retrieved)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+
+	for _, res := range r.Results {
+		status := "ok"
+		switch {
+		case res.Divergent():
+			status = "DIVERGENT"
+		case res.Erratum != "":
+			status = "erratum"
+		case !verbose:
+			continue
+		}
+
+		ident := res.Ident
+		if ident == "" {
+			ident = "----"
+		}
+		fmt.Fprintf(w, "%-9s %s published %s, computed %s; %s, %s, printed %q",
+			status, ident, res.Effective, res.Computed, res.Source, res.Location, res.Printed)
+		if res.Erratum != "" {
+			fmt.Fprintf(w, "; %s", res.Erratum)
+		}
+		fmt.Fprintln(w)
+	}
+
+	div := len(r.Divergences())
+	if div == 0 {
+		_, err := fmt.Fprintf(w, "\nOK: all %d dates match, %d known errata\n", len(r.Results)-len(r.Errata()), len(r.Errata()))
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\nFAILED: %d of %d dates diverge\n", div, len(r.Results))
+	return err
+}
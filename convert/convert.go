@@ -0,0 +1,236 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package convert adds AIRAC cycle columns to CSV data. It reads a column of
+// dates or timestamps and appends the identifier and/or the effective date of
+// the matching AIRAC cycle to each row. Rows are streamed, so memory use does
+// not depend on the size of the input.
+package convert
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jwkohnen/airac"
+)
+
+// Columns selects the columns to append.
+type Columns int
+
+const (
+	// Ident appends the identifier of the cycle, e.g. "2101".
+	Ident Columns = 1 << iota
+
+	// Effective appends the effective date of the cycle, e.g. "2021-01-28".
+	Effective
+
+	// Both appends the identifier and the effective date.
+	Both = Ident | Effective
+)
+
+// DefaultLayouts are the time layouts tried if Options.Layouts is empty.
+// Values without a time zone are interpreted as UTC.
+//
+// nolint:gochecknoglobals
+var DefaultLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+const dateFormat = "2006-01-02"
+
+// Options configures Convert.
+type Options struct {
+	// Column is the zero-based index of the column holding the dates.
+	Column int
+
+	// ColumnName selects the column holding the dates by its name in the
+	// header. It takes precedence over Column and requires Header.
+	ColumnName string
+
+	// Header treats the first row as a header. The names of the appended
+	// columns are "airac" and "airac_effective".
+	Header bool
+
+	// Append selects the columns to append. Zero means Ident.
+	Append Columns
+
+	// Comma is the field delimiter. Zero means ','.
+	Comma rune
+
+	// Layouts are the time layouts tried in order. If empty,
+	// DefaultLayouts is used.
+	Layouts []string
+
+	// Precise lets cycles change at 00:01 UTC rather than at midnight.
+	Precise bool
+
+	// OnError is called for each row whose date does not parse. The row is
+	// written with empty cycle columns.
+	OnError func(err *RowError)
+}
+
+// RowError is a row whose date could not be converted.
+type RowError struct {
+	// Line is the line number of the date field in the input, starting at 1.
+	Line  int
+	Value string
+	Err   error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error { return e.Err }
+
+// Result summarizes a conversion.
+type Result struct {
+	// Rows is the number of data rows, not counting the header.
+	Rows int
+
+	// Failed is the number of rows whose date did not parse.
+	Failed int
+}
+
+// ErrNoColumn is returned if the selected column does not exist.
+var ErrNoColumn = errors.New("no such column")
+
+// Convert reads CSV from r and writes it to w with cycle columns appended. An
+// error is returned for malformed CSV and for I/O errors; rows whose date does
+// not parse are reported to OnError instead.
+func Convert(r io.Reader, w io.Writer, opts Options) (Result, error) {
+	if opts.Append == 0 {
+		opts.Append = Ident
+	}
+	if opts.Comma == 0 {
+		opts.Comma = ','
+	}
+	if len(opts.Layouts) == 0 {
+		opts.Layouts = DefaultLayouts
+	}
+
+	cr := csv.NewReader(r)
+	cr.Comma = opts.Comma
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	cw := csv.NewWriter(w)
+	cw.Comma = opts.Comma
+
+	var res Result
+
+	column := opts.Column
+	if opts.Header {
+		header, err := cr.Read()
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return res, err
+		}
+
+		if opts.ColumnName != "" {
+			column = indexOf(header, opts.ColumnName)
+			if column < 0 {
+				return res, fmt.Errorf("%w %q", ErrNoColumn, opts.ColumnName)
+			}
+		}
+
+		if opts.Append&Ident != 0 {
+			header = append(header, "airac")
+		}
+		if opts.Append&Effective != 0 {
+			header = append(header, "airac_effective")
+		}
+		if err := cw.Write(header); err != nil {
+			return res, err
+		}
+	} else if opts.ColumnName != "" {
+		return res, fmt.Errorf("%w %q: selecting a column by name requires a header", ErrNoColumn, opts.ColumnName)
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return res, err
+		}
+		res.Rows++
+
+		if column < 0 || column >= len(record) {
+			line, _ := cr.FieldPos(0)
+			return res, fmt.Errorf("line %d: %w %d", line, ErrNoColumn, column)
+		}
+
+		ident, effective := "", ""
+		if a, err := opts.cycle(record[column]); err != nil {
+			res.Failed++
+			if opts.OnError != nil {
+				line, _ := cr.FieldPos(column)
+				opts.OnError(&RowError{Line: line, Value: record[column], Err: err})
+			}
+		} else {
+			ident, effective = a.String(), a.Effective().Format(dateFormat)
+		}
+
+		if opts.Append&Ident != 0 {
+			record = append(record, ident)
+		}
+		if opts.Append&Effective != 0 {
+			record = append(record, effective)
+		}
+		if err := cw.Write(record); err != nil {
+			return res, err
+		}
+	}
+
+	cw.Flush()
+	return res, cw.Error()
+}
+
+func (o Options) cycle(value string) (airac.AIRAC, error) {
+	for _, layout := range o.Layouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if o.Precise {
+			return airac.FromDatePrecise(t), nil
+		}
+		return airac.FromDate(t), nil
+	}
+
+	return 0, fmt.Errorf("illegal date %q", value)
+}
+
+func indexOf(header []string, name string) int {
+	for i, h := range header {
+		if h == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	in := "flight,departure,arrival\n" +
+		"LH400,2021-01-27T21:40:00Z,2021-01-28T09:15:00Z\n" +
+		"\"LH\n401\",2021-01-28 00:00,2021-01-28 12:00\n" +
+		"LH402,yesterday,2021-01-29\n"
+
+	var out bytes.Buffer
+	var errs []string
+	res, err := Convert(strings.NewReader(in), &out, Options{
+		Header:     true,
+		ColumnName: "departure",
+		Append:     Both,
+		OnError:    func(e *RowError) { errs = append(errs, e.Error()) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "flight,departure,arrival,airac,airac_effective\n" +
+		"LH400,2021-01-27T21:40:00Z,2021-01-28T09:15:00Z,2014,2020-12-31\n" +
+		"\"LH\n401\",2021-01-28 00:00,2021-01-28 12:00,2101,2021-01-28\n" +
+		"LH402,yesterday,2021-01-29,,\n"
+	if got := out.String(); got != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+
+	if res.Rows != 3 || res.Failed != 1 {
+		t.Errorf("want 3 rows, 1 failed, got %+v", res)
+	}
+
+	if wantErrs := []string{`line 5: illegal date "yesterday"`}; fmt.Sprint(errs) != fmt.Sprint(wantErrs) {
+		t.Errorf("want %q, got %q", wantErrs, errs)
+	}
+}
+
+func TestConvertNoHeader(t *testing.T) {
+	t.Parallel()
+
+	in := "a;2021-01-28 00:00:30\nb;2021-01-28 00:01:00\n"
+
+	var out bytes.Buffer
+	_, err := Convert(strings.NewReader(in), &out, Options{Column: 1, Comma: ';', Precise: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "a;2021-01-28 00:00:30;2014\nb;2021-01-28 00:01:00;2101\n"
+	if got := out.String(); got != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestConvertNoColumn(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		in   string
+		opts Options
+	}{
+		{"a,b\n1,2\n", Options{Header: true, ColumnName: "c"}},
+		{"1,2\n", Options{ColumnName: "a"}},
+		{"1,2\n", Options{Column: 2}},
+	}
+
+	for _, tt := range testt {
+		if _, err := Convert(strings.NewReader(tt.in), io.Discard, tt.opts); !errors.Is(err, ErrNoColumn) {
+			t.Errorf("%+v: want %v, got %v", tt.opts, ErrNoColumn, err)
+		}
+	}
+}
+
+// rows generates n CSV rows without holding them in memory.
+type rows struct {
+	n, i int
+	buf  []byte
+}
+
+func (r *rows) Read(p []byte) (int, error) {
+	for len(r.buf) < len(p) && r.i < r.n {
+		r.buf = append(r.buf, fmt.Sprintf("%d,2021-02-%02d\n", r.i, r.i%28+1)...)
+		r.i++
+	}
+	if len(r.buf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func TestConvertStreaming(t *testing.T) {
+	t.Parallel()
+
+	const n = 100000
+
+	res, err := Convert(&rows{n: n}, io.Discard, Options{Column: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Rows != n || res.Failed != 0 {
+		t.Errorf("want %d rows, 0 failed, got %+v", n, res)
+	}
+}
+
+func BenchmarkConvert(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Convert(&rows{n: 1000}, io.Discard, Options{Column: 1, Append: Both}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func ExampleConvert() {
+	in := "id,timestamp\n42,2021-02-03T10:00:00Z\n"
+
+	_, err := Convert(strings.NewReader(in), os.Stdout, Options{Header: true, Column: 1, Append: Both})
+	if err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// id,timestamp,airac,airac_effective
+	// 42,2021-02-03T10:00:00Z,2101,2021-01-28
+}
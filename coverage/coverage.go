@@ -0,0 +1,264 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package coverage audits an archive of datasets that are each labelled with
+// an AIRAC cycle or a range of cycles. It reports the cycles no dataset covers
+// (gaps), the cycles more than one dataset covers (overlaps) and the longest
+// contiguous run of covered cycles within a period.
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jwkohnen/airac"
+)
+
+// empty is an empty range of cycles; the zero Range is cycle 0.
+// nolint:gochecknoglobals
+var empty = airac.Range{First: 1, Last: 0}
+
+// Dataset is an archived dataset and the cycles it covers.
+type Dataset struct {
+	// Name identifies the dataset, e.g. its file name.
+	Name string
+
+	Cycles airac.Range
+}
+
+// ByChrono is a []Dataset wrapper that satisfies sort.Interface and sorts
+// datasets chronologically by their first cycle, then by their last cycle and
+// name.
+type ByChrono []Dataset
+
+// Len is the number of elements in the collection.
+func (c ByChrono) Len() int { return len(c) }
+
+// Less reports whether the element with index i should sort before the element
+// with index j.
+func (c ByChrono) Less(i, j int) bool {
+	a, b := c[i].Cycles, c[j].Cycles
+	switch {
+	case a.First != b.First:
+		return a.First < b.First
+	case a.Last != b.Last:
+		return a.Last < b.Last
+	default:
+		return c[i].Name < c[j].Name
+	}
+}
+
+// Swap swaps the elements with indexes i and j.
+func (c ByChrono) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+
+// static assert
+var _ sort.Interface = (ByChrono)(nil)
+
+// Overlap is a range of cycles that the same two or more datasets cover.
+type Overlap struct {
+	Cycles airac.Range
+
+	// Datasets are the names of the datasets covering the cycles, in
+	// chronological order.
+	Datasets []string
+}
+
+// Report is the coverage of a period by a list of datasets.
+type Report struct {
+	// Period is the analyzed range of cycles. It is empty if there are no
+	// datasets to analyze.
+	Period airac.Range
+
+	// Datasets are the analyzed datasets in chronological order, including
+	// those outside of the period.
+	Datasets []Dataset
+
+	// Covered is the set of cycles of the period covered by at least one
+	// dataset.
+	Covered airac.Set
+
+	// Gaps are the ranges of cycles of the period no dataset covers.
+	Gaps []airac.Range
+
+	// Overlaps are the ranges of cycles of the period more than one dataset
+	// covers, split where the set of datasets changes.
+	Overlaps []Overlap
+
+	// Longest is the longest contiguous run of covered cycles of the period,
+	// the earliest one if there are several. It is empty if no cycle is
+	// covered.
+	Longest airac.Range
+}
+
+// OK reports whether every cycle of the period is covered exactly once.
+func (r Report) OK() bool { return len(r.Gaps) == 0 && len(r.Overlaps) == 0 }
+
+// Missing returns the number of cycles of the period no dataset covers.
+func (r Report) Missing() int { return r.Period.Len() - r.Covered.Len() }
+
+// Ratio returns the fraction of the cycles of the period that are covered,
+// from 0 to 1. An empty period has a ratio of 0.
+func (r Report) Ratio() float64 {
+	if r.Period.Empty() {
+		return 0
+	}
+	return float64(r.Covered.Len()) / float64(r.Period.Len())
+}
+
+// Span returns the range from the first to the last cycle any of the datasets
+// covers, and false if there are no datasets with cycles.
+func Span(datasets []Dataset) (airac.Range, bool) {
+	var span airac.Range
+	found := false
+	for _, d := range datasets {
+		if d.Cycles.Empty() {
+			continue
+		}
+		if !found || d.Cycles.First < span.First {
+			span.First = d.Cycles.First
+		}
+		if !found || d.Cycles.Last > span.Last {
+			span.Last = d.Cycles.Last
+		}
+		found = true
+	}
+	return span, found
+}
+
+// Analyze reports the coverage of the span of all datasets. It does not modify
+// datasets.
+func Analyze(datasets []Dataset) Report {
+	span, ok := Span(datasets)
+	if !ok {
+		return Report{Period: empty, Longest: empty, Datasets: sorted(datasets)}
+	}
+	return AnalyzePeriod(datasets, span)
+}
+
+// AnalyzePeriod reports the coverage of period. Cycles of the datasets outside
+// of period are ignored. It does not modify datasets.
+func AnalyzePeriod(datasets []Dataset, period airac.Range) Report {
+	r := Report{Period: period, Longest: empty, Datasets: sorted(datasets)}
+	if period.Empty() {
+		return r
+	}
+
+	for _, d := range r.Datasets {
+		r.Covered.AddRange(clip(d.Cycles, period))
+	}
+	r.Gaps = airac.SetFromRanges(period).Difference(r.Covered).Ranges()
+
+	r.Longest = empty
+	for _, run := range r.Covered.Ranges() {
+		if run.Len() > r.Longest.Len() {
+			r.Longest = run
+		}
+	}
+
+	r.Overlaps = overlaps(r.Datasets, period)
+
+	return r
+}
+
+// overlaps sweeps the period along the boundaries of the datasets, which are
+// in chronological order, and collects the segments covered more than once.
+func overlaps(datasets []Dataset, period airac.Range) []Overlap {
+	// Boundaries are the first cycle of each segment; int avoids the
+	// overflow of the cycle after the last one.
+	var bounds []int
+	for _, d := range datasets {
+		c := clip(d.Cycles, period)
+		if c.Empty() {
+			continue
+		}
+		bounds = append(bounds, int(c.First), int(c.Last)+1)
+	}
+	sort.Ints(bounds)
+
+	var res []Overlap
+	for i := 0; i+1 < len(bounds); i++ {
+		if bounds[i] == bounds[i+1] {
+			continue
+		}
+		seg := airac.Range{First: airac.AIRAC(bounds[i]), Last: airac.AIRAC(bounds[i+1] - 1)}
+
+		var names []string
+		for _, d := range datasets {
+			if d.Cycles.Contains(seg.First) {
+				names = append(names, d.Name)
+			}
+		}
+		if len(names) < 2 {
+			continue
+		}
+
+		if n := len(res); n > 0 && int(res[n-1].Cycles.Last)+1 == int(seg.First) && slices.Equal(res[n-1].Datasets, names) {
+			res[n-1].Cycles.Last = seg.Last
+			continue
+		}
+		res = append(res, Overlap{Cycles: seg, Datasets: names})
+	}
+
+	return res
+}
+
+// Read reads a list of datasets, one per line, in the form "<cycles> [name]",
+// where cycles is a cycle or range as accepted by airac.ParseRange and name is
+// the rest of the line, e.g. "2101..2103 navdata-2101.zip". Without a name,
+// the cycles are the name. Blank lines and lines starting with "#" are
+// ignored.
+func Read(r io.Reader) ([]Dataset, error) {
+	var datasets []Dataset
+
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		label, name := text, text
+		if i := strings.IndexAny(text, " \t"); i >= 0 {
+			label, name = text[:i], strings.TrimSpace(text[i+1:])
+		}
+
+		cycles, err := airac.ParseRange(label)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		datasets = append(datasets, Dataset{Name: name, Cycles: cycles})
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return datasets, nil
+}
+
+func sorted(datasets []Dataset) []Dataset {
+	s := make([]Dataset, len(datasets))
+	copy(s, datasets)
+	sort.Stable(ByChrono(s))
+	return s
+}
+
+func clip(r, to airac.Range) airac.Range {
+	return airac.Range{First: max(r.First, to.First), Last: min(r.Last, to.Last)}
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package coverage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jwkohnen/airac"
+)
+
+const archive = `
+# navdata archive
+2101 nav-2101.zip
+2102..2104 nav-2102-2104.zip
+2104 nav-2104-fix.zip
+2108-2110 nav-2108.zip
+2109 nav-2109.zip
+2109	nav-2109 copy.zip
+2112
+`
+
+func rng(t *testing.T, s string) airac.Range {
+	t.Helper()
+	r, err := airac.ParseRange(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func overlapsString(overlaps []Overlap) string {
+	s := make([]string, len(overlaps))
+	for i, o := range overlaps {
+		s[i] = fmt.Sprintf("%s %s", o.Cycles, strings.Join(o.Datasets, "+"))
+	}
+	return strings.Join(s, "; ")
+}
+
+func rangesString(ranges []airac.Range) string {
+	return airac.SetFromRanges(ranges...).String()
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	datasets, err := Read(strings.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(datasets) != 7 {
+		t.Fatalf("want 7 datasets, got %d", len(datasets))
+	}
+	if d := datasets[1]; d.Name != "nav-2102-2104.zip" || d.Cycles.String() != "2102-2104" {
+		t.Errorf("want nav-2102-2104.zip 2102-2104, got %s %s", d.Name, d.Cycles)
+	}
+	if d := datasets[5]; d.Name != "nav-2109 copy.zip" {
+		t.Errorf("want nav-2109 copy.zip, got %q", d.Name)
+	}
+	if d := datasets[6]; d.Name != "2112" || d.Cycles.String() != "2112" {
+		t.Errorf("want 2112 2112, got %s %s", d.Name, d.Cycles)
+	}
+
+	_, err = Read(strings.NewReader("2101 a\n\n2199 b\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3: ") {
+		t.Errorf("want error on line 3, got %v", err)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	datasets, err := Read(strings.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := Analyze(datasets)
+
+	if got := r.Period.String(); got != "2101-2112" {
+		t.Errorf("want period 2101-2112, got %s", got)
+	}
+	if got := r.Covered.String(); got != "2101-2104,2108-2110,2112" {
+		t.Errorf("want covered 2101-2104,2108-2110,2112, got %s", got)
+	}
+	if got := rangesString(r.Gaps); got != "2105-2107,2111" {
+		t.Errorf("want gaps 2105-2107,2111, got %s", got)
+	}
+	want := "2104 nav-2102-2104.zip+nav-2104-fix.zip; 2109 nav-2108.zip+nav-2109 copy.zip+nav-2109.zip"
+	if got := overlapsString(r.Overlaps); got != want {
+		t.Errorf("want overlaps %q, got %q", want, got)
+	}
+	if got := r.Longest.String(); got != "2101-2104" {
+		t.Errorf("want longest 2101-2104, got %s", got)
+	}
+	if r.Missing() != 4 || r.Ratio() != 8.0/12 || r.OK() {
+		t.Errorf("want 4 missing, ratio 8/12, not OK, got %d, %v, %v", r.Missing(), r.Ratio(), r.OK())
+	}
+	if !sort.IsSorted(ByChrono(r.Datasets)) || datasets[0].Name != "nav-2101.zip" {
+		t.Errorf("want sorted copy of the datasets")
+	}
+}
+
+func TestAnalyzePeriod(t *testing.T) {
+	t.Parallel()
+
+	datasets := []Dataset{
+		{"a", rng(t, "2101-2106")},
+		{"b", rng(t, "2104-2110")},
+		{"c", rng(t, "2105-2108")},
+		{"d", rng(t, "2201-2203")},
+	}
+
+	testt := []struct {
+		period   string
+		gaps     string
+		overlaps string
+		longest  string
+		ok       bool
+	}{
+		{"2101-2113", "2111-2113", "2104 a+b; 2105-2106 a+b+c; 2107-2108 b+c", "2101-2110", false},
+		{"2107-2108", "", "2107-2108 b+c", "2107-2108", false},
+		{"2201-2202", "", "", "2201-2202", true},
+		{"2012-2101", "2012-2014", "", "2101", false},
+		{"2301-2302", "2301-2302", "", "", false},
+	}
+
+	for _, tt := range testt {
+		r := AnalyzePeriod(datasets, rng(t, tt.period))
+		if got := rangesString(r.Gaps); got != tt.gaps {
+			t.Errorf("%s: want gaps %q, got %q", tt.period, tt.gaps, got)
+		}
+		if got := overlapsString(r.Overlaps); got != tt.overlaps {
+			t.Errorf("%s: want overlaps %q, got %q", tt.period, tt.overlaps, got)
+		}
+		if got := r.Longest.String(); got != tt.longest {
+			t.Errorf("%s: want longest %q, got %q", tt.period, tt.longest, got)
+		}
+		if r.OK() != tt.ok {
+			t.Errorf("%s: want OK %v, got %v", tt.period, tt.ok, r.OK())
+		}
+	}
+}
+
+func TestAnalyzeEmpty(t *testing.T) {
+	t.Parallel()
+
+	r := Analyze(nil)
+	if !r.Period.Empty() || !r.Longest.Empty() || !r.Covered.Empty() || r.Ratio() != 0 || !r.OK() {
+		t.Errorf("want empty report, got %+v", r)
+	}
+}
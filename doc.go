@@ -25,6 +25,14 @@ published in the ICAO Aeronautical Information Services Manual (DOC 8126;
 AN/872; 6th Edition; 2003). Test cases validate documented dates from 1998 until
 2020, including the rare case of a 14th cycle in the year 2020.
 
+The schedule is purely arithmetic. Where reality deviates from it, e.g. a
+cycle is published off-schedule or withdrawn, a Calendar layers a table of
+overrides on top of it and offers the same queries.
+
+A Set holds a collection of cycles, e.g. the coverage of an archive, and a
+Timeline holds a value that changes with cycles, e.g. reference data that is
+effective from a cycle until superseded.
+
 Licensed under the Apache License, Version 2.0.
 */
 package airac
@@ -49,7 +57,8 @@ package airac
      dates, 00:01 UTC must be used to indicate the time when the AIRAC-based
      information will become effective."
 
-   However I won't "fix" this, because that may just confuse users. */
+   However I won't "fix" this, because that may just confuse users. Use the
+   Precise variants, e.g. FromDatePrecise, where 00:01 UTC matters. */
 
 // nolint:godox
 /* BUG(jwkohnen): Calculations that include calendar dates before the internal
@@ -57,7 +66,7 @@ package airac
    and after year 2192 may silently produce wrong data. */
 
 // nolint:godox
-/* BUG(jwkohnen): This package only provides calculations on effective dates,
-   not publication or reception dates etc. Although effective dates are clearly
+/* BUG(jwkohnen): Milestone dates (publication, reception etc.) are calculated
+   with today's offsets for all cycles. Although effective dates are clearly
    defined and are consistent at least between 1998 until 2020, the derivative
    dates changed historically.[citation needed] */
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import "time"
+
+// PreciseOffset is the time of day at which AIRAC information becomes
+// effective, 00:01 UTC (ICAO DOC 8126, 6th edition, paragraph 2.6.4).
+const PreciseOffset = time.Minute
+
+// EffectivePrecise returns the instant this AIRAC cycle becomes effective, i.e.
+// 00:01 UTC on its effective date.
+func (a AIRAC) EffectivePrecise() time.Time {
+	return a.Effective().Add(PreciseOffset)
+}
+
+// FromDatePrecise returns the AIRAC cycle that is effective at the instant t,
+// with cycles changing at 00:01 UTC rather than at midnight.
+func FromDatePrecise(t time.Time) AIRAC {
+	return FromDate(t.Add(-PreciseOffset))
+}
+
+// Span is the part of a time interval that falls into a single AIRAC cycle.
+// It starts at Start inclusive and ends at End exclusive.
+type Span struct {
+	Cycle AIRAC
+	Start time.Time
+	End   time.Time
+}
+
+// CyclesDuring returns the AIRAC cycles the time interval from start inclusive
+// to end exclusive touches, in chronological order, each with the sub-interval
+// it covers. If end equals start, the cycle at start is returned with an empty
+// span. If end is before start, it returns nil.
+func CyclesDuring(start, end time.Time) []Span {
+	return cyclesDuring(start, end, 0)
+}
+
+// CyclesDuringPrecise is like CyclesDuring, but cycles change at 00:01 UTC
+// rather than at midnight.
+func CyclesDuringPrecise(start, end time.Time) []Span {
+	return cyclesDuring(start, end, PreciseOffset)
+}
+
+func cyclesDuring(start, end time.Time, offset time.Duration) []Span {
+	if end.Before(start) {
+		return nil
+	}
+
+	var spans []Span
+	for a := FromDate(start.Add(-offset)); ; a++ {
+		s := Span{Cycle: a, Start: start, End: (a + 1).Effective().Add(offset)}
+		if !s.End.Before(end) {
+			s.End = end
+			return append(spans, s)
+		}
+
+		spans = append(spans, s)
+		start = s.End
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFromDatePrecise(t *testing.T) {
+	t.Parallel()
+
+	a := FromStringMust("2101")
+
+	if got := FromDatePrecise(a.Effective()); got != a-1 {
+		t.Errorf("00:00 UTC: want %s, got %s", a-1, got)
+	}
+	if got := FromDatePrecise(a.EffectivePrecise()); got != a {
+		t.Errorf("00:01 UTC: want %s, got %s", a, got)
+	}
+	if got := FromDatePrecise(a.EffectivePrecise().Add(-time.Nanosecond)); got != a-1 {
+		t.Errorf("00:00:59.999999999 UTC: want %s, got %s", a-1, got)
+	}
+}
+
+// nolint:funlen
+func TestCyclesDuring(t *testing.T) {
+	t.Parallel()
+
+	const layout = "2006-01-02 15:04"
+
+	testt := []struct {
+		name    string
+		start   string
+		end     string
+		precise bool
+		want    []string
+	}{
+		{
+			name:  "within one cycle",
+			start: "2021-01-27 10:00", end: "2021-01-27 22:00",
+			want: []string{"2014 2021-01-27 10:00 2021-01-27 22:00"},
+		},
+		{
+			name:  "across a cycle change",
+			start: "2021-01-27 22:00", end: "2021-01-28 08:00",
+			want: []string{
+				"2014 2021-01-27 22:00 2021-01-28 00:00",
+				"2101 2021-01-28 00:00 2021-01-28 08:00",
+			},
+		},
+		{
+			name:  "ends at the cycle change",
+			start: "2021-01-27 22:00", end: "2021-01-28 00:00",
+			want: []string{"2014 2021-01-27 22:00 2021-01-28 00:00"},
+		},
+		{
+			name:  "ends one minute after midnight, precise",
+			start: "2021-01-27 22:00", end: "2021-01-28 00:01", precise: true,
+			want: []string{"2014 2021-01-27 22:00 2021-01-28 00:01"},
+		},
+		{
+			name:  "across a cycle change, precise",
+			start: "2021-01-27 23:30", end: "2021-01-28 00:30", precise: true,
+			want: []string{
+				"2014 2021-01-27 23:30 2021-01-28 00:01",
+				"2101 2021-01-28 00:01 2021-01-28 00:30",
+			},
+		},
+		{
+			name:  "three cycles",
+			start: "2021-01-01 00:00", end: "2021-03-01 00:00",
+			want: []string{
+				"2014 2021-01-01 00:00 2021-01-28 00:00",
+				"2101 2021-01-28 00:00 2021-02-25 00:00",
+				"2102 2021-02-25 00:00 2021-03-01 00:00",
+			},
+		},
+		{
+			name:  "empty interval",
+			start: "2021-01-28 00:00", end: "2021-01-28 00:00",
+			want: []string{"2101 2021-01-28 00:00 2021-01-28 00:00"},
+		},
+		{
+			name:  "reversed interval",
+			start: "2021-01-28 00:00", end: "2021-01-27 00:00",
+			want: nil,
+		},
+	}
+
+	for _, tt := range testt {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			start, err := time.Parse(layout, tt.start)
+			if err != nil {
+				t.Fatal(err)
+			}
+			end, err := time.Parse(layout, tt.end)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			spans := CyclesDuring(start, end)
+			if tt.precise {
+				spans = CyclesDuringPrecise(start, end)
+			}
+
+			got := make([]string, 0, len(spans))
+			for _, s := range spans {
+				got = append(got, fmt.Sprintf("%s %s %s", s.Cycle, s.Start.Format(layout), s.End.Format(layout)))
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func ExampleCyclesDuring() {
+	departure := time.Date(2021, time.January, 27, 21, 40, 0, 0, time.UTC)
+	arrival := time.Date(2021, time.January, 28, 9, 15, 0, 0, time.UTC)
+
+	for _, s := range CyclesDuringPrecise(departure, arrival) {
+		fmt.Printf("%s: %s - %s\n", s.Cycle, s.Start.Format("Jan 2 15:04"), s.End.Format("Jan 2 15:04"))
+	}
+
+	// Output:
+	// 2014: Jan 27 21:40 - Jan 28 00:01
+	// 2101: Jan 28 00:01 - Jan 28 09:15
+}
@@ -1,3 +1,5 @@
 module github.com/jwkohnen/airac
 
-go 1.16
+go 1.23
+
+require google.golang.org/protobuf v1.36.11
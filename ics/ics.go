@@ -0,0 +1,237 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ics writes AIRAC cycles as an iCalendar (RFC 5545) file that
+// calendar applications like Outlook or Thunderbird can import or subscribe
+// to.
+//
+// Each cycle becomes an all-day event on its effective date, optionally
+// accompanied by events for its milestones. Events carry stable UIDs derived
+// from the cycle identifier, so importing an updated file updates existing
+// events instead of duplicating them.
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/monitor"
+)
+
+const (
+	// DefaultDomain is the domain part of the UIDs if Options.Domain is
+	// empty.
+	DefaultDomain = "airac.jwkohnen.github.com"
+
+	prodID     = "-//jwkohnen//airac//EN"
+	dateFormat = "20060102"
+	stampFmt   = "20060102T150405Z"
+
+	// maxLine is the maximum length of a content line in octets, excluding
+	// the line break.
+	maxLine = 75
+)
+
+// Options configures the calendar.
+type Options struct {
+	// Name is the display name of the calendar. If empty, no name is set.
+	Name string
+
+	// Milestones adds an event for each milestone of each cycle.
+	Milestones bool
+
+	// Alarms adds a reminder to each event for each duration, which is the
+	// time before the start of the event, e.g. 7*24*time.Hour for a
+	// reminder one week ahead. Durations are truncated to whole minutes.
+	Alarms []time.Duration
+
+	// Domain is the domain part of the UIDs of the events. If empty,
+	// DefaultDomain is used.
+	Domain string
+
+	// Stamp is the creation time of the events (DTSTAMP). If zero, the
+	// current time is used.
+	Stamp time.Time
+}
+
+// Write writes a calendar of the cycles in r to w. Use airac.CyclesInYear for
+// the cycles of a year.
+func Write(w io.Writer, r airac.Range, opts Options) error {
+	if opts.Domain == "" {
+		opts.Domain = DefaultDomain
+	}
+	if opts.Stamp.IsZero() {
+		opts.Stamp = time.Now()
+	}
+
+	cw := &writer{w: bufio.NewWriter(w)}
+
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:" + prodID)
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	if opts.Name != "" {
+		cw.line("X-WR-CALNAME:" + escape(opts.Name))
+	}
+
+	for _, a := range r.Cycles() {
+		info := a.Info()
+		cw.event(opts, event{
+			uid:     fmt.Sprintf("%s-effective@%s", a, opts.Domain),
+			date:    a.Effective(),
+			summary: fmt.Sprintf("AIRAC %s effective", a),
+			description: fmt.Sprintf("AIRAC cycle %s is effective from %s to %s.",
+				a, info.Effective, info.Expires),
+		})
+
+		if !opts.Milestones {
+			continue
+		}
+		for _, m := range airac.Milestones() {
+			cw.event(opts, event{
+				uid:     fmt.Sprintf("%s-%s@%s", a, strings.ReplaceAll(m.String(), " ", "-"), opts.Domain),
+				date:    a.Milestone(m),
+				summary: fmt.Sprintf("AIRAC %s %s", a, m),
+				description: fmt.Sprintf("%s of AIRAC cycle %s, %d days before it is effective on %s.",
+					capitalize(m.String()), a, int(-m.Offset().Hours()/24), info.Effective),
+			})
+		}
+	}
+
+	cw.line("END:VCALENDAR")
+
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+type event struct {
+	uid         string
+	date        time.Time
+	summary     string
+	description string
+}
+
+type writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (cw *writer) event(opts Options, e event) {
+	cw.line("BEGIN:VEVENT")
+	cw.line("UID:" + escape(e.uid))
+	cw.line("DTSTAMP:" + opts.Stamp.UTC().Format(stampFmt))
+	cw.line("DTSTART;VALUE=DATE:" + e.date.Format(dateFormat))
+	cw.line("DTEND;VALUE=DATE:" + e.date.AddDate(0, 0, 1).Format(dateFormat))
+	cw.line("SUMMARY:" + escape(e.summary))
+	cw.line("DESCRIPTION:" + escape(e.description))
+	cw.line("TRANSP:TRANSPARENT")
+
+	for _, d := range opts.Alarms {
+		cw.line("BEGIN:VALARM")
+		cw.line("ACTION:DISPLAY")
+		cw.line("DESCRIPTION:" + escape(e.summary))
+		cw.line("TRIGGER:" + duration(-d))
+		cw.line("END:VALARM")
+	}
+
+	cw.line("END:VEVENT")
+}
+
+// line writes a content line terminated by CRLF, folded after maxLine octets
+// without splitting UTF-8 sequences.
+func (cw *writer) line(s string) {
+	if cw.err != nil {
+		return
+	}
+
+	limit := maxLine
+	for len(s) > limit {
+		i := limit
+		for i > 0 && !utf8Start(s[i]) {
+			i--
+		}
+		_, cw.err = cw.w.WriteString(s[:i] + "\r\n ")
+		if cw.err != nil {
+			return
+		}
+		s = s[i:]
+		limit = maxLine - 1 // the leading space of a continuation line counts
+	}
+
+	_, cw.err = cw.w.WriteString(s + "\r\n")
+}
+
+func utf8Start(b byte) bool { return b&0xC0 != 0x80 }
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// ParseAlarm parses the lead time of an alarm, either a number of days like 7d
+// or 7, or a time.Duration like 12h. Negative lead times are illegal.
+func ParseAlarm(s string) (time.Duration, error) {
+	if n, err := monitor.ParseDays(s); err == nil {
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("illegal alarm %q, want days or a duration, e.g. 7d or 12h", s)
+}
+
+// duration formats d as an RFC 5545 duration in whole minutes, e.g. -P7D or
+// -PT1H30M.
+func duration(d time.Duration) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
+	}
+	b.WriteByte('P')
+
+	minutes := int64(d / time.Minute)
+	days, hours, minutes := minutes/(24*60), minutes/60%24, minutes%60
+
+	if days > 0 || (hours == 0 && minutes == 0) {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if hours > 0 || minutes > 0 {
+		b.WriteByte('T')
+		if hours > 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+		if minutes > 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+	}
+
+	return b.String()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
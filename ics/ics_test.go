@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ics
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwkohnen/airac"
+)
+
+// nolint:gochecknoglobals
+var stamp = time.Date(2021, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	r := airac.Range{First: airac.FromStringMust("2101"), Last: airac.FromStringMust("2102")}
+	if err := Write(&buf, r, Options{Stamp: stamp}); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"BEGIN:VEVENT\r\nUID:2101-effective@" + DefaultDomain + "\r\nDTSTAMP:20210101T120000Z\r\n" +
+			"DTSTART;VALUE=DATE:20210128\r\nDTEND;VALUE=DATE:20210129\r\nSUMMARY:AIRAC 2101 effective\r\n",
+		"UID:2102-effective@",
+		"DTSTART;VALUE=DATE:20210225\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in\n%s", want, got)
+		}
+	}
+
+	if n := strings.Count(got, "BEGIN:VEVENT"); n != 2 {
+		t.Errorf("want 2 events, got %d", n)
+	}
+	if strings.Contains(got, "VALARM") {
+		t.Errorf("want no alarms, got\n%s", got)
+	}
+}
+
+func TestWriteMilestonesAndAlarms(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	r := airac.Range{First: airac.FromStringMust("2101"), Last: airac.FromStringMust("2101")}
+	opts := Options{
+		Name:       "AIRAC, 2101",
+		Milestones: true,
+		Alarms:     []time.Duration{7 * 24 * time.Hour, 90 * time.Minute},
+		Domain:     "example.com",
+		Stamp:      stamp,
+	}
+	if err := Write(&buf, r, opts); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"X-WR-CALNAME:AIRAC\\, 2101\r\n",
+		"UID:2101-effective@example.com\r\n",
+		"UID:2101-submission-cut-off@example.com\r\nDTSTAMP:20210101T120000Z\r\nDTSTART;VALUE=DATE:20201203\r\n",
+		"UID:2101-publication@example.com\r\nDTSTAMP:20210101T120000Z\r\nDTSTART;VALUE=DATE:20201217\r\n",
+		"UID:2101-reception@example.com\r\nDTSTAMP:20210101T120000Z\r\nDTSTART;VALUE=DATE:20201231\r\n",
+		"TRIGGER:-P7D\r\n",
+		"TRIGGER:-PT1H30M\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in\n%s", want, got)
+		}
+	}
+
+	if n := strings.Count(got, "BEGIN:VEVENT"); n != 4 {
+		t.Errorf("want 4 events, got %d", n)
+	}
+	if n := strings.Count(got, "BEGIN:VALARM"); n != 8 {
+		t.Errorf("want 8 alarms, got %d", n)
+	}
+}
+
+func TestWriteStableUIDs(t *testing.T) {
+	t.Parallel()
+
+	uids := func(r airac.Range, stamp time.Time) map[string]bool {
+		var buf bytes.Buffer
+		if err := Write(&buf, r, Options{Milestones: true, Stamp: stamp}); err != nil {
+			t.Fatal(err)
+		}
+		m := make(map[string]bool)
+		for _, l := range strings.Split(buf.String(), "\r\n") {
+			if strings.HasPrefix(l, "UID:") {
+				m[l] = true
+			}
+		}
+		return m
+	}
+
+	year := uids(airac.CyclesInYear(2021), stamp)
+	later := uids(airac.Range{First: airac.FromStringMust("2105"), Last: airac.FromStringMust("2106")}, stamp.Add(time.Hour))
+
+	for uid := range later {
+		if !year[uid] {
+			t.Errorf("want %s in the calendar of the year", uid)
+		}
+	}
+	if got, want := len(year), 13*4; got != want {
+		t.Errorf("want %d distinct UIDs, got %d", want, got)
+	}
+}
+
+func TestLineFolding(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	cw := &writer{w: bufio.NewWriter(&buf)}
+	long := "DESCRIPTION:" + strings.Repeat("äöü", 40)
+	cw.line(long)
+	if err := cw.w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("want folded lines, got %q", buf.String())
+	}
+
+	var unfolded strings.Builder
+	for i, l := range lines {
+		if len(l) > maxLine {
+			t.Errorf("line %d: want at most %d octets, got %d", i, maxLine, len(l))
+		}
+		if i > 0 {
+			if !strings.HasPrefix(l, " ") {
+				t.Errorf("line %d: want leading space, got %q", i, l)
+			}
+			l = l[1:]
+		}
+		unfolded.WriteString(l)
+	}
+
+	if got := unfolded.String(); got != long {
+		t.Errorf("want %q, got %q", long, got)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "P0D"},
+		{-24 * time.Hour, "-P1D"},
+		{-7 * 24 * time.Hour, "-P7D"},
+		{-time.Hour, "-PT1H"},
+		{-90 * time.Minute, "-PT1H30M"},
+		{-(25*time.Hour + time.Minute), "-P1DT1H1M"},
+		{-(time.Minute + 30*time.Second), "-PT1M"},
+	}
+
+	for _, tt := range testt {
+		if got := duration(tt.d); got != tt.want {
+			t.Errorf("%v: want %s, got %s", tt.d, tt.want, got)
+		}
+	}
+}
+
+func TestParseAlarm(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"7d", 7 * 24 * time.Hour, true},
+		{"7", 7 * 24 * time.Hour, true},
+		{"0d", 0, true},
+		{"12h", 12 * time.Hour, true},
+		{"1h30m", 90 * time.Minute, true},
+		{"-7d", 0, false},
+		{"-1h", 0, false},
+		{"d", 0, false},
+		{"a week", 0, false},
+	}
+
+	for _, tt := range testt {
+		got, err := ParseAlarm(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("%q: want %v, ok %v, got %v, %v", tt.in, tt.want, tt.ok, got, err)
+		}
+	}
+}
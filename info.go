@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import "time"
+
+// Info is the structured form of an AIRAC cycle, e.g. for encoding as JSON.
+// Dates are formatted as YYYY-MM-DD.
+type Info struct {
+	Ident      string          `json:"ident"`
+	Year       int             `json:"year"`
+	Ordinal    int             `json:"ordinal"`
+	Effective  string          `json:"effective"`
+	Expires    string          `json:"expires"`
+	Milestones []MilestoneInfo `json:"milestones"`
+}
+
+// MilestoneInfo is the structured form of a milestone of an AIRAC cycle.
+type MilestoneInfo struct {
+	Name string `json:"name"`
+	Date string `json:"date"`
+}
+
+// Info returns the structured form of this AIRAC cycle. Expires is the last day
+// the cycle is effective, like in LongString.
+func (a AIRAC) Info() Info {
+	info := Info{
+		Ident:     a.String(),
+		Year:      a.Year(),
+		Ordinal:   a.Ordinal(),
+		Effective: a.Effective().Format(format),
+		Expires:   (a + 1).Effective().Add(-1).Format(format),
+	}
+
+	for _, m := range Milestones() {
+		info.Milestones = append(info.Milestones, MilestoneInfo{Name: m.String(), Date: a.Milestone(m).Format(format)})
+	}
+
+	return info
+}
+
+// CyclesInYear returns the range of AIRAC cycles whose identifiers carry the
+// given year, i.e. 13 or, rarely, 14 cycles.
+func CyclesInYear(year int) Range {
+	return Range{
+		First: FromDate(time.Date(year-1, time.December, 31, 0, 0, 0, 0, time.UTC)) + 1,
+		Last:  FromDate(time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)),
+	}
+}
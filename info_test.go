@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestCyclesInYear(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		year int
+		want string
+	}{
+		{1998, "9801-9814"},
+		{2019, "1901-1913"},
+		{2020, "2001-2014"},
+		{2021, "2101-2113"},
+	}
+
+	for _, tt := range testt {
+		if got := CyclesInYear(tt.year).String(); got != tt.want {
+			t.Errorf("%d: want %s, got %s", tt.year, tt.want, got)
+		}
+	}
+}
+
+func TestCyclesInYearLen(t *testing.T) {
+	t.Parallel()
+
+	for year := _epoch.Year() + 1; year < 2193; year++ {
+		r := CyclesInYear(year)
+		if r.Len() != 13 && r.Len() != 14 {
+			t.Errorf("%d: want 13 or 14 cycles, got %d", year, r.Len())
+		}
+		if r.First.Year() != year || r.Last.Year() != year || r.First.Ordinal() != 1 {
+			t.Errorf("%d: wrong range %s", year, r)
+		}
+	}
+}
+
+func ExampleAIRAC_Info() {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(FromStringMust("2101").Info()); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// {
+	//   "ident": "2101",
+	//   "year": 2021,
+	//   "ordinal": 1,
+	//   "effective": "2021-01-28",
+	//   "expires": "2021-02-24",
+	//   "milestones": [
+	//     {
+	//       "name": "submission cut-off",
+	//       "date": "2020-12-03"
+	//     },
+	//     {
+	//       "name": "publication",
+	//       "date": "2020-12-17"
+	//     },
+	//     {
+	//       "name": "reception",
+	//       "date": "2020-12-31"
+	//     }
+	//   ]
+	// }
+}
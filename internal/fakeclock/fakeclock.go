@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package fakeclock provides a manually driven clock for tests. It satisfies
+// airac.Clock.
+package fakeclock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a fake clock. Time only passes by calling Advance or Set.
+//
+// Like the runtime's timers, channels returned by After follow a monotonic
+// clock that only Advance moves. Set only moves the wall clock, which models a
+// clock jump or a suspended system.
+type Clock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	mono    time.Duration
+	waiters []waiter
+}
+
+type waiter struct {
+	deadline time.Duration
+	c        chan time.Time
+}
+
+// New returns a Clock set to now.
+func New(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current fake time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the fake time once it has advanced by
+// at least d.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+
+	c.waiters = append(c.waiters, waiter{deadline: c.mono + d, c: ch})
+	c.cond.Broadcast()
+	return ch
+}
+
+// Advance moves the wall clock and the monotonic clock forward by d and fires
+// all due waiters.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	c.mono += d
+
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline > c.mono {
+			pending = append(pending, w)
+			continue
+		}
+		w.c <- c.now
+	}
+	c.waiters = pending
+}
+
+// Set sets the wall clock to t, which may be in the past. No waiter fires.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// BlockUntil blocks until at least n callers wait on channels returned by
+// After.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"fmt"
+	"time"
+)
+
+// Milestone is a date in the publication process of an AIRAC cycle, at a fixed
+// offset before its effective date.
+type Milestone int
+
+const (
+	// SubmissionCutOff is the last day to submit changes for the cycle to the
+	// AIS, 56 days before the effective date. This matches the advance notice
+	// that ICAO Annex 15 recommends for major changes.
+	SubmissionCutOff Milestone = iota
+
+	// Publication is the latest date of publication, 42 days before the
+	// effective date (ICAO DOC 8126, 6th edition, paragraph 2.6.3).
+	Publication
+
+	// Reception is the date the publication should have reached its
+	// recipients, 28 days before the effective date (ICAO DOC 8126, 6th
+	// edition, paragraph 2.6.3).
+	Reception
+)
+
+// Milestones lists all milestones in chronological order.
+func Milestones() []Milestone {
+	return []Milestone{SubmissionCutOff, Publication, Reception}
+}
+
+// Offset returns the offset of the milestone relative to the effective date.
+// It is negative.
+func (m Milestone) Offset() time.Duration {
+	const day = 24 * time.Hour
+
+	switch m {
+	case SubmissionCutOff:
+		return -56 * day
+	case Publication:
+		return -42 * day
+	case Reception:
+		return -28 * day
+	default:
+		panic(fmt.Sprintf("illegal milestone %d", int(m)))
+	}
+}
+
+// String returns the name of the milestone.
+func (m Milestone) String() string {
+	switch m {
+	case SubmissionCutOff:
+		return "submission cut-off"
+	case Publication:
+		return "publication"
+	case Reception:
+		return "reception"
+	default:
+		return fmt.Sprintf("Milestone(%d)", int(m))
+	}
+}
+
+// Milestone returns the date of milestone m for this AIRAC cycle.
+func (a AIRAC) Milestone(m Milestone) time.Time {
+	return a.Effective().Add(m.Offset())
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMilestone(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		airac     string
+		milestone Milestone
+		date      string
+	}{
+		{"2101", SubmissionCutOff, "2020-12-03"},
+		{"2101", Publication, "2020-12-17"},
+		{"2101", Reception, "2020-12-31"},
+		{"2014", Publication, "2020-11-19"},
+	}
+
+	for _, tt := range testt {
+		got := FromStringMust(tt.airac).Milestone(tt.milestone).Format(format)
+		if got != tt.date {
+			t.Errorf("%s %s: want %s, got %s", tt.airac, tt.milestone, tt.date, got)
+		}
+	}
+}
+
+func TestMilestonesChronological(t *testing.T) {
+	t.Parallel()
+
+	var prev time.Duration = -1 << 63
+	for _, m := range Milestones() {
+		if m.Offset() <= prev {
+			t.Errorf("milestone %s out of order", m)
+		}
+		if m.Offset() >= 0 {
+			t.Errorf("milestone %s not before the effective date", m)
+		}
+		prev = m.Offset()
+	}
+}
+
+func ExampleAIRAC_Milestone() {
+	airac := FromStringMust("2101")
+	for _, m := range Milestones() {
+		fmt.Printf("%-18s %s\n", m, airac.Milestone(m).Format("2006-01-02"))
+	}
+
+	// Output:
+	// submission cut-off 2020-12-03
+	// publication        2020-12-17
+	// reception          2020-12-31
+}
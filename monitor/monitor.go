@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package monitor watches the currency of a dataset that is tagged with an
+// AIRAC cycle or a range of cycles, and reports whether it is current, about
+// to expire, expired or not yet effective.
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jwkohnen/airac"
+)
+
+const day = 24 * time.Hour
+
+// State is the currency state of a dataset.
+type State int
+
+const (
+	// Current means the dataset is effective and does not expire soon.
+	Current State = iota + 1
+
+	// ExpiringSoon means the dataset is effective but expires within the
+	// warning threshold.
+	ExpiringSoon
+
+	// Expired means the last cycle of the dataset has been superseded.
+	Expired
+
+	// NotYetEffective means the first cycle of the dataset is not yet
+	// effective.
+	NotYetEffective
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case Current:
+		return "current"
+	case ExpiringSoon:
+		return "expiring soon"
+	case Expired:
+		return "expired"
+	case NotYetEffective:
+		return "not yet effective"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Status is the state of a dataset at a point in time.
+type Status struct {
+	State   State
+	Dataset airac.Range
+	At      time.Time
+
+	// Remaining is the time until the dataset expires. It is negative if the
+	// dataset has expired.
+	Remaining time.Duration
+}
+
+// Check returns the status of dataset at t. The dataset is ExpiringSoon within
+// warnDays days before it expires.
+func Check(dataset airac.Range, t time.Time, warnDays int) Status {
+	s := Status{
+		Dataset:   dataset,
+		At:        t,
+		Remaining: dataset.Expires().Sub(t),
+	}
+
+	switch {
+	case t.Before(dataset.Effective()):
+		s.State = NotYetEffective
+	case s.Remaining <= 0:
+		s.State = Expired
+	case s.Remaining <= time.Duration(warnDays)*day:
+		s.State = ExpiringSoon
+	default:
+		s.State = Current
+	}
+
+	return s
+}
+
+// ParseDays parses a number of days like 7d or 7, e.g. the warnDays of Check.
+// Negative numbers are illegal.
+func ParseDays(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("illegal number of days %q, want e.g. 7d", s)
+	}
+	return n, nil
+}
+
+// Options configures a Monitor.
+type Options struct {
+	// Clock is the time source. If nil, airac.SystemClock is used.
+	Clock airac.Clock
+
+	// WarnDays is the number of days before expiry in which the dataset is
+	// ExpiringSoon.
+	WarnDays int
+
+	// Recheck is the longest time to wait before the clock is consulted
+	// again, see airac.NextWakeup. Zero means airac.DefaultRecheck.
+	Recheck time.Duration
+
+	// OnChange is called by Run with the initial status and on each change
+	// of the state.
+	OnChange func(Status)
+}
+
+// Monitor watches the currency of a dataset.
+type Monitor struct {
+	dataset airac.Range
+	opts    Options
+}
+
+// New returns a Monitor for dataset. For a dataset of a single cycle a, use
+// airac.Range{First: a, Last: a}.
+func New(dataset airac.Range, opts Options) *Monitor {
+	if opts.Clock == nil {
+		opts.Clock = airac.SystemClock{}
+	}
+
+	return &Monitor{dataset: dataset, opts: opts}
+}
+
+// Status returns the current status of the dataset.
+func (m *Monitor) Status() Status {
+	return Check(m.dataset, m.opts.Clock.Now(), m.opts.WarnDays)
+}
+
+// Run calls OnChange with the initial status and on each change of the state
+// until ctx is done. It returns ctx.Err().
+func (m *Monitor) Run(ctx context.Context) error {
+	var last State
+	for {
+		s := m.Status()
+		if s.State != last && m.opts.OnChange != nil {
+			m.opts.OnChange(s)
+		}
+		last = s.State
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.opts.Clock.After(m.sleep(s.At)):
+		}
+	}
+}
+
+// Watch runs the monitor in the background and delivers the initial status
+// and each change of the state on the returned channel, which is closed once
+// ctx is done. OnChange is not called.
+func (m *Monitor) Watch(ctx context.Context) <-chan Status {
+	c := make(chan Status, 1)
+
+	w := *m
+	w.opts.OnChange = func(s Status) {
+		select {
+		case c <- s:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(c)
+		_ = w.Run(ctx)
+	}()
+
+	return c
+}
+
+// sleep returns the time from now until the next possible change of the state.
+func (m *Monitor) sleep(now time.Time) time.Duration {
+	expires := m.dataset.Expires()
+
+	return airac.NextWakeup(now, m.opts.Recheck,
+		m.dataset.Effective(),
+		expires.Add(-time.Duration(m.opts.WarnDays)*day),
+		expires,
+	)
+}
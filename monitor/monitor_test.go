@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/internal/fakeclock"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	single := airac.Range{First: airac.FromStringMust("2101"), Last: airac.FromStringMust("2101")}
+	multi := airac.Range{First: airac.FromStringMust("2101"), Last: airac.FromStringMust("2103")}
+
+	testt := []struct {
+		dataset airac.Range
+		at      string
+		want    State
+	}{
+		{single, "2021-01-27 23:59", NotYetEffective},
+		{single, "2021-01-28 00:00", Current},
+		{single, "2021-02-17 23:59", Current},
+		{single, "2021-02-18 00:00", ExpiringSoon},
+		{single, "2021-02-24 23:59", ExpiringSoon},
+		{single, "2021-02-25 00:00", Expired},
+		{multi, "2021-02-25 00:00", Current},
+		{multi, "2021-04-22 00:00", Expired},
+	}
+
+	for _, tt := range testt {
+		got := Check(tt.dataset, date(tt.at), 7)
+		if got.State != tt.want {
+			t.Errorf("%s at %s: want %s, got %s", tt.dataset, tt.at, tt.want, got.State)
+		}
+	}
+}
+
+func TestCheckRemaining(t *testing.T) {
+	t.Parallel()
+
+	a := airac.FromStringMust("2101")
+	s := Check(airac.Range{First: a, Last: a}, date("2021-02-20 12:00"), 7)
+
+	if want := 4*day + 12*time.Hour; s.Remaining != want {
+		t.Errorf("want %s, got %s", want, s.Remaining)
+	}
+}
+
+func TestParseDays(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"7d", 7, true},
+		{"7", 7, true},
+		{"0d", 0, true},
+		{"-7d", 0, false},
+		{"12h", 0, false},
+		{"d", 0, false},
+		{"a week", 0, false},
+	}
+
+	for _, tt := range testt {
+		got, err := ParseDays(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("%q: want %d, ok %v, got %d, %v", tt.in, tt.want, tt.ok, got, err)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	a := airac.FromStringMust("2101")
+	clock := fakeclock.New(date("2021-01-27 12:00"))
+	m := New(airac.Range{First: a, Last: a}, Options{Clock: clock, WarnDays: 7})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := m.Watch(ctx)
+
+	testt := []struct {
+		at   string
+		want State
+	}{
+		{"2021-01-27 12:00", NotYetEffective},
+		{"2021-02-04 12:00", Current},
+		{"2021-02-20 12:00", ExpiringSoon},
+		{"2021-03-30 12:00", Expired},
+	}
+
+	for i, tt := range testt {
+		if i > 0 {
+			// jump past the next boundary, as if waking up from suspend
+			clock.BlockUntil(1)
+			clock.Set(date(tt.at))
+			clock.Advance(airac.DefaultRecheck)
+		}
+
+		if got := <-c; got.State != tt.want {
+			t.Errorf("%s: want %s, got %s", tt.at, tt.want, got.State)
+		}
+	}
+
+	cancel()
+	for range c {
+		t.Error("no more changes expected")
+	}
+}
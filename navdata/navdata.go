@@ -0,0 +1,266 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package navdata reads the AIRAC cycle that simulator navigation databases
+// declare and checks it against the AIRAC schedule.
+//
+// Two dialects are recognized. The cycle_info.txt files that ship with X-Plane
+// navdata and with other simulator add-ons state the cycle, a revision and the
+// validity period as "key: value" lines, e.g.
+//
+//	AIRAC cycle    : 2101
+//	Version        : 1
+//	Valid (from/to): 28/JAN/2021 - 25/FEB/2021
+//
+// The headers of X-Plane .dat files like earth_nav.dat only state the cycle,
+// e.g.
+//
+//	1150 Version - data cycle 2101, build 20210115, metadata NavXP1150.
+package navdata
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jwkohnen/airac"
+)
+
+const dateFormat = "2006-01-02"
+
+// Dialect is the format a cycle info was read from.
+type Dialect int
+
+const (
+	// CycleInfo is the "key: value" format of cycle_info.txt files.
+	CycleInfo Dialect = iota + 1
+
+	// DatHeader is the header line of X-Plane .dat files.
+	DatHeader
+)
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case CycleInfo:
+		return "cycle_info.txt"
+	case DatHeader:
+		return "dat header"
+	default:
+		return fmt.Sprintf("Dialect(%d)", int(d))
+	}
+}
+
+// Info is the cycle information of a navigation database.
+type Info struct {
+	// Dialect is the format the information was read from.
+	Dialect Dialect
+
+	// Cycle is the stated AIRAC cycle.
+	Cycle airac.AIRAC
+
+	// Revision is the stated revision or version of the data within the
+	// cycle. It is empty if not stated.
+	Revision string
+
+	// Validity is the stated validity period as written. It is empty if not
+	// stated.
+	Validity string
+
+	// ValidFrom and ValidTo are the parsed dates of Validity. ValidTo is
+	// either the last day of the cycle or the effective date of the next
+	// cycle, depending on the vendor. Both are zero if no validity is stated.
+	ValidFrom time.Time
+	ValidTo   time.Time
+}
+
+// ErrNoCycle is returned by Parse if the input does not state an AIRAC cycle.
+var ErrNoCycle = errors.New("no AIRAC cycle stated") // nolint:gochecknoglobals
+
+// MismatchError reports that the stated validity dates do not match the
+// effective dates of the stated cycle.
+type MismatchError struct {
+	Cycle     airac.AIRAC
+	ValidFrom time.Time
+	ValidTo   time.Time
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("AIRAC cycle %s is effective %s to %s, but validity is stated as %s to %s",
+		e.Cycle, e.Cycle.Effective().Format(dateFormat), (e.Cycle + 1).Effective().Add(-1).Format(dateFormat),
+		e.ValidFrom.Format(dateFormat), e.ValidTo.Format(dateFormat))
+}
+
+// nolint:gochecknoglobals
+var (
+	datHeader = regexp.MustCompile(`(?i)\bdata cycle (\d{4})\b`)
+
+	// compactValidity matches e.g. 28JAN-25FEB/21.
+	compactValidity = regexp.MustCompile(`^(\d{1,2}[A-Za-z]{3})\s*-\s*(\d{1,2}[A-Za-z]{3})/(\d{2})$`)
+
+	// dateLayouts are the layouts of dates in validity periods.
+	dateLayouts = []string{"02/Jan/2006", "2/Jan/2006", "2006-01-02", "02.01.2006", "02Jan2006", "02 Jan 2006", "2 Jan 2006"}
+)
+
+// Parse reads cycle information in any of the dialects. It does not check the
+// stated dates, see Validate.
+func Parse(r io.Reader) (*Info, error) {
+	sc := bufio.NewScanner(r)
+
+	var info Info
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(strings.TrimPrefix(sc.Text(), "\ufeff"))
+		if text == "" {
+			continue
+		}
+
+		if m := datHeader.FindStringSubmatch(text); m != nil && info.Dialect == 0 {
+			a, err := airac.FromString(m[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			return &Info{Dialect: DatHeader, Cycle: a}, nil
+		}
+
+		key, value, ok := strings.Cut(text, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch normalizeKey(key) {
+		case "airac cycle", "airac", "cycle":
+			a, err := airac.FromString(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			info.Dialect, info.Cycle = CycleInfo, a
+		case "version", "revision":
+			info.Revision = value
+		case "valid (from/to)", "valid from/to", "valid", "validity":
+			from, to, err := parseValidity(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			info.Validity, info.ValidFrom, info.ValidTo = value, from, to
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if info.Dialect == 0 {
+		return nil, ErrNoCycle
+	}
+
+	return &info, nil
+}
+
+// ParseFile reads cycle information from the named file.
+func ParseFile(name string) (*Info, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return info, nil
+}
+
+// Validate checks that the stated validity matches the effective dates of the
+// stated cycle. It returns a *MismatchError if not. Information without a
+// stated validity is valid.
+func (i *Info) Validate() error {
+	if i.ValidFrom.IsZero() {
+		return nil
+	}
+
+	next := (i.Cycle + 1).Effective()
+	if !i.ValidFrom.Equal(i.Cycle.Effective()) || !(i.ValidTo.Equal(next) || i.ValidTo.Equal(next.AddDate(0, 0, -1))) {
+		return &MismatchError{Cycle: i.Cycle, ValidFrom: i.ValidFrom, ValidTo: i.ValidTo}
+	}
+
+	return nil
+}
+
+// Current reports whether the stated cycle is effective at t.
+func (i *Info) Current(t time.Time) bool {
+	return airac.FromDate(t) == i.Cycle
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.Join(strings.Fields(key), " "))
+}
+
+// parseValidity parses a validity period like "28/JAN/2021 - 25/FEB/2021" or
+// "28JAN-25FEB/21".
+func parseValidity(s string) (from, to time.Time, err error) {
+	if m := compactValidity.FindStringSubmatch(s); m != nil {
+		if to, err = time.Parse("2Jan06", m[2]+m[3]); err != nil {
+			return from, to, fmt.Errorf("illegal validity %q", s)
+		}
+		if from, err = time.Parse("2Jan06", m[1]+m[3]); err != nil {
+			return from, to, fmt.Errorf("illegal validity %q", s)
+		}
+		if from.After(to) {
+			// the period spans the turn of the year, e.g. 31DEC-27JAN/21
+			from = from.AddDate(-1, 0, 0)
+		}
+		return from, to, nil
+	}
+
+	// Split at a dash that separates two dates; ISO 8601 dates contain
+	// dashes themselves.
+	for i := strings.Index(s, "-"); i >= 0; {
+		if from, to, ok := parseDates(s[:i], s[i+1:]); ok {
+			return from, to, nil
+		}
+		j := strings.Index(s[i+1:], "-")
+		if j < 0 {
+			break
+		}
+		i += j + 1
+	}
+
+	return from, to, fmt.Errorf("illegal validity %q", s)
+}
+
+func parseDates(from, to string) (time.Time, time.Time, bool) {
+	f, ok := parseDate(strings.TrimSpace(from))
+	if !ok {
+		return f, f, false
+	}
+	t, ok := parseDate(strings.TrimSpace(to))
+	return f, t, ok
+}
+
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package navdata
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// nolint:funlen
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		name     string
+		in       string
+		dialect  Dialect
+		cycle    string
+		revision string
+		from, to string
+	}{
+		{
+			"x-plane",
+			"AIRAC cycle    : 2101\nVersion        : 1\nValid (from/to): 28/JAN/2021 - 25/FEB/2021\n",
+			CycleInfo, "2101", "1", "2021-01-28", "2021-02-25",
+		},
+		{
+			"windows line ends and BOM",
+			"\ufeffAIRAC cycle    : 2101\r\nVersion        : 2\r\nValid (from/to): 28/JAN/2021 - 24/FEB/2021\r\n",
+			CycleInfo, "2101", "2", "2021-01-28", "2021-02-24",
+		},
+		{
+			"compact",
+			"AIRAC Cycle: 2102\nRevision: 3\nValid: 25FEB-24MAR/21\n",
+			CycleInfo, "2102", "3", "2021-02-25", "2021-03-24",
+		},
+		{
+			"compact turn of year",
+			"AIRAC Cycle: 2014\nValid: 31DEC-27JAN/21\n",
+			CycleInfo, "2014", "", "2020-12-31", "2021-01-27",
+		},
+		{
+			"iso",
+			"cycle: 2101\nvalidity: 2021-01-28 - 2021-02-25\n",
+			CycleInfo, "2101", "", "2021-01-28", "2021-02-25",
+		},
+		{
+			"iso without spaces",
+			"cycle: 2101\nvalidity: 2021-01-28-2021-02-25\n",
+			CycleInfo, "2101", "", "2021-01-28", "2021-02-25",
+		},
+		{
+			"no validity",
+			"AIRAC cycle: 2101\n",
+			CycleInfo, "2101", "", "", "",
+		},
+		{
+			"dat header",
+			"I\n1150 Version - data cycle 2101, build 20210115, metadata NavXP1150. Copyright (c) 2021, Navigraph.\n\n",
+			DatHeader, "2101", "", "", "",
+		},
+	}
+
+	for _, tt := range testt {
+		info, err := Parse(strings.NewReader(tt.in))
+		if err != nil {
+			t.Errorf("%s: want nil, got %v", tt.name, err)
+			continue
+		}
+
+		if info.Dialect != tt.dialect {
+			t.Errorf("%s: want dialect %v, got %v", tt.name, tt.dialect, info.Dialect)
+		}
+		if got := info.Cycle.String(); got != tt.cycle {
+			t.Errorf("%s: want cycle %s, got %s", tt.name, tt.cycle, got)
+		}
+		if info.Revision != tt.revision {
+			t.Errorf("%s: want revision %q, got %q", tt.name, tt.revision, info.Revision)
+		}
+		if got := format(info.ValidFrom); got != tt.from {
+			t.Errorf("%s: want valid from %q, got %q", tt.name, tt.from, got)
+		}
+		if got := format(info.ValidTo); got != tt.to {
+			t.Errorf("%s: want valid to %q, got %q", tt.name, tt.to, got)
+		}
+		if err := info.Validate(); err != nil {
+			t.Errorf("%s: want valid, got %v", tt.name, err)
+		}
+	}
+}
+
+func format(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateFormat)
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		name string
+		in   string
+	}{
+		{"empty", ""},
+		{"illegal cycle", "AIRAC cycle: 2115\n"},
+		{"illegal validity", "AIRAC cycle: 2101\nValid (from/to): soon\n"},
+		{"illegal dat header", "1150 Version - data cycle 2199, build 20210115\n"},
+	}
+
+	for _, tt := range testt {
+		if _, err := Parse(strings.NewReader(tt.in)); err == nil {
+			t.Errorf("%s: want error, got nil", tt.name)
+		}
+	}
+
+	if _, err := Parse(strings.NewReader("Version: 1\n")); !errors.Is(err, ErrNoCycle) {
+		t.Errorf("want %v, got %v", ErrNoCycle, err)
+	}
+}
+
+func TestValidateMismatch(t *testing.T) {
+	t.Parallel()
+
+	info, err := Parse(strings.NewReader("AIRAC cycle: 2102\nValid (from/to): 28/JAN/2021 - 25/FEB/2021\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = info.Validate()
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("want *MismatchError, got %v", err)
+	}
+
+	want := "AIRAC cycle 2102 is effective 2021-02-25 to 2021-03-24, but validity is stated as 2021-01-28 to 2021-02-25"
+	if got := err.Error(); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	t.Parallel()
+
+	info, err := Parse(strings.NewReader("AIRAC cycle: 2101\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testt := []struct {
+		date string
+		want bool
+	}{
+		{"2021-01-27", false},
+		{"2021-01-28", true},
+		{"2021-02-24", true},
+		{"2021-02-25", false},
+	}
+
+	for _, tt := range testt {
+		d, err := time.Parse(dateFormat, tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Current(d); got != tt.want {
+			t.Errorf("%s: want %t, got %t", tt.date, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"fmt"
+	"time"
+)
+
+// DaysPerCycle is the number of days of an AIRAC cycle.
+const DaysPerCycle = 28
+
+// Position is the position of an instant within its AIRAC cycle.
+type Position struct {
+	// Cycle is the AIRAC cycle effective at the instant.
+	Cycle AIRAC
+
+	// Day is the day of the cycle, from 1 to DaysPerCycle.
+	Day int
+
+	// Elapsed is the time since the cycle became effective, and Remaining
+	// the time until the next cycle becomes effective.
+	Elapsed   time.Duration
+	Remaining time.Duration
+
+	// Progress is the elapsed fraction of the cycle, from 0 inclusive to 1
+	// exclusive.
+	Progress float64
+}
+
+// String returns the position like "day 17 of 28, 11 days until 2103". The
+// days until the next cycle do not count the current day.
+func (p Position) String() string {
+	return fmt.Sprintf("day %d of %d, %s until %s", p.Day, DaysPerCycle, days(DaysPerCycle-p.Day), p.Cycle+1)
+}
+
+// PositionAt returns the position of t within the AIRAC cycle effective at t.
+func PositionAt(t time.Time) Position {
+	return positionAt(t, 0)
+}
+
+// PositionAtPrecise is like PositionAt, but cycles change at 00:01 UTC rather
+// than at midnight. Days of the cycle also start at 00:01 UTC.
+func PositionAtPrecise(t time.Time) Position {
+	return positionAt(t, PreciseOffset)
+}
+
+func positionAt(t time.Time, offset time.Duration) Position {
+	a := FromDate(t.Add(-offset))
+	elapsed := t.Sub(a.Effective().Add(offset))
+
+	return Position{
+		Cycle:     a,
+		Day:       int(elapsed/(24*time.Hour)) + 1,
+		Elapsed:   elapsed,
+		Remaining: cycleDuration - elapsed,
+		Progress:  float64(elapsed) / float64(cycleDuration),
+	}
+}
+
+// DayOfCycle returns the day of the AIRAC cycle effective at t, from 1 to 28.
+func DayOfCycle(t time.Time) int { return PositionAt(t).Day }
+
+// DayOfCyclePrecise is like DayOfCycle, but cycles and their days start at
+// 00:01 UTC.
+func DayOfCyclePrecise(t time.Time) int { return PositionAtPrecise(t).Day }
+
+// Remaining returns the time from t until the next AIRAC cycle becomes
+// effective.
+func Remaining(t time.Time) time.Duration { return PositionAt(t).Remaining }
+
+// RemainingPrecise is like Remaining, but cycles change at 00:01 UTC.
+func RemainingPrecise(t time.Time) time.Duration { return PositionAtPrecise(t).Remaining }
+
+// Progress returns the elapsed fraction of the AIRAC cycle effective at t,
+// from 0 inclusive to 1 exclusive.
+func Progress(t time.Time) float64 { return PositionAt(t).Progress }
+
+// ProgressPrecise is like Progress, but cycles change at 00:01 UTC.
+func ProgressPrecise(t time.Time) float64 { return PositionAtPrecise(t).Progress }
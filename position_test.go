@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPositionAt(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		t         time.Time
+		cycle     string
+		day       int
+		remaining time.Duration
+		progress  float64
+	}{
+		{date("2021-01-28"), "2101", 1, 28 * 24 * time.Hour, 0},
+		{date("2021-01-28").Add(12 * time.Hour), "2101", 1, 27*24*time.Hour + 12*time.Hour, 0.5 / 28},
+		{date("2021-02-13"), "2101", 17, 12 * 24 * time.Hour, 16.0 / 28},
+		{date("2021-02-11"), "2101", 15, 14 * 24 * time.Hour, 0.5},
+		{date("2021-02-25").Add(-time.Nanosecond), "2101", 28, time.Nanosecond, 1 - 1/float64(cycleDuration)},
+	}
+
+	for _, tt := range testt {
+		p := PositionAt(tt.t)
+		if p.Cycle.String() != tt.cycle || p.Day != tt.day || p.Remaining != tt.remaining || p.Progress != tt.progress {
+			t.Errorf("%s: want %s day %d, %s remaining, progress %v, got %s day %d, %s, %v",
+				tt.t, tt.cycle, tt.day, tt.remaining, tt.progress, p.Cycle, p.Day, p.Remaining, p.Progress)
+		}
+		if p.Elapsed+p.Remaining != 28*24*time.Hour {
+			t.Errorf("%s: want elapsed and remaining to add up to 28 days, got %s + %s", tt.t, p.Elapsed, p.Remaining)
+		}
+		if DayOfCycle(tt.t) != p.Day || Remaining(tt.t) != p.Remaining || Progress(tt.t) != p.Progress {
+			t.Errorf("%s: want the functions to match the position", tt.t)
+		}
+	}
+}
+
+func TestPositionAtPrecise(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		t         time.Time
+		cycle     string
+		day       int
+		remaining time.Duration
+	}{
+		{date("2021-01-28"), "2014", 28, time.Minute},
+		{date("2021-01-28").Add(time.Minute), "2101", 1, 28 * 24 * time.Hour},
+		{date("2021-01-29"), "2101", 1, 27*24*time.Hour + time.Minute},
+		{date("2021-01-29").Add(time.Minute), "2101", 2, 27 * 24 * time.Hour},
+	}
+
+	for _, tt := range testt {
+		p := PositionAtPrecise(tt.t)
+		if p.Cycle.String() != tt.cycle || p.Day != tt.day || p.Remaining != tt.remaining {
+			t.Errorf("%s: want %s day %d, %s remaining, got %s day %d, %s",
+				tt.t, tt.cycle, tt.day, tt.remaining, p.Cycle, p.Day, p.Remaining)
+		}
+		if DayOfCyclePrecise(tt.t) != p.Day || RemainingPrecise(tt.t) != p.Remaining || ProgressPrecise(tt.t) != p.Progress {
+			t.Errorf("%s: want the functions to match the position", tt.t)
+		}
+	}
+}
+
+func ExamplePositionAt() {
+	p := PositionAt(time.Date(2021, time.March, 13, 15, 0, 0, 0, time.UTC))
+
+	fmt.Println(p)
+	fmt.Printf("%.0f%% of %s\n", p.Progress*100, p.Cycle)
+	// Output:
+	// day 17 of 28, 11 days until 2103
+	// 59% of 2102
+}
@@ -0,0 +1,254 @@
+//
+// Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: proto/airac.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AiracMessage is an AIRAC cycle. airac19010110, the number of cycles since
+// the internal epoch 1901-01-10, identifies the cycle; the other fields are
+// derived from it for the convenience of consumers.
+type AiracMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Airac19010110 uint32                 `protobuf:"varint,1,opt,name=airac19010110,proto3" json:"airac19010110,omitempty"`
+	// ident is the identifier YYOO, e.g. "2101".
+	Ident   string `protobuf:"bytes,2,opt,name=ident,proto3" json:"ident,omitempty"`
+	Year    int32  `protobuf:"varint,3,opt,name=year,proto3" json:"year,omitempty"`
+	Ordinal int32  `protobuf:"varint,4,opt,name=ordinal,proto3" json:"ordinal,omitempty"`
+	// effective is the instant the cycle becomes effective, 00:00 UTC on its
+	// effective date.
+	Effective *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=effective,proto3" json:"effective,omitempty"`
+	// expires is the instant the cycle is superseded by the next one, i.e. the
+	// effective instant of the next cycle.
+	Expires       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires,proto3" json:"expires,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AiracMessage) Reset() {
+	*x = AiracMessage{}
+	mi := &file_proto_airac_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AiracMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AiracMessage) ProtoMessage() {}
+
+func (x *AiracMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_airac_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AiracMessage.ProtoReflect.Descriptor instead.
+func (*AiracMessage) Descriptor() ([]byte, []int) {
+	return file_proto_airac_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AiracMessage) GetAirac19010110() uint32 {
+	if x != nil {
+		return x.Airac19010110
+	}
+	return 0
+}
+
+func (x *AiracMessage) GetIdent() string {
+	if x != nil {
+		return x.Ident
+	}
+	return ""
+}
+
+func (x *AiracMessage) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *AiracMessage) GetOrdinal() int32 {
+	if x != nil {
+		return x.Ordinal
+	}
+	return 0
+}
+
+func (x *AiracMessage) GetEffective() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Effective
+	}
+	return nil
+}
+
+func (x *AiracMessage) GetExpires() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+// AiracRange is a contiguous range of cycles from first to last inclusive.
+type AiracRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	First         *AiracMessage          `protobuf:"bytes,1,opt,name=first,proto3" json:"first,omitempty"`
+	Last          *AiracMessage          `protobuf:"bytes,2,opt,name=last,proto3" json:"last,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AiracRange) Reset() {
+	*x = AiracRange{}
+	mi := &file_proto_airac_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AiracRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AiracRange) ProtoMessage() {}
+
+func (x *AiracRange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_airac_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AiracRange.ProtoReflect.Descriptor instead.
+func (*AiracRange) Descriptor() ([]byte, []int) {
+	return file_proto_airac_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AiracRange) GetFirst() *AiracMessage {
+	if x != nil {
+		return x.First
+	}
+	return nil
+}
+
+func (x *AiracRange) GetLast() *AiracMessage {
+	if x != nil {
+		return x.Last
+	}
+	return nil
+}
+
+var File_proto_airac_proto protoreflect.FileDescriptor
+
+const file_proto_airac_proto_rawDesc = "" +
+	"\n" +
+	"\x11proto/airac.proto\x12\x05proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe8\x01\n" +
+	"\fAiracMessage\x12$\n" +
+	"\rairac19010110\x18\x01 \x01(\rR\rairac19010110\x12\x14\n" +
+	"\x05ident\x18\x02 \x01(\tR\x05ident\x12\x12\n" +
+	"\x04year\x18\x03 \x01(\x05R\x04year\x12\x18\n" +
+	"\aordinal\x18\x04 \x01(\x05R\aordinal\x128\n" +
+	"\teffective\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\teffective\x124\n" +
+	"\aexpires\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aexpires\"`\n" +
+	"\n" +
+	"AiracRange\x12)\n" +
+	"\x05first\x18\x01 \x01(\v2\x13.proto.AiracMessageR\x05first\x12'\n" +
+	"\x04last\x18\x02 \x01(\v2\x13.proto.AiracMessageR\x04lastB!Z\x1fgithub.com/jwkohnen/airac/protob\x06proto3"
+
+var (
+	file_proto_airac_proto_rawDescOnce sync.Once
+	file_proto_airac_proto_rawDescData []byte
+)
+
+func file_proto_airac_proto_rawDescGZIP() []byte {
+	file_proto_airac_proto_rawDescOnce.Do(func() {
+		file_proto_airac_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_airac_proto_rawDesc), len(file_proto_airac_proto_rawDesc)))
+	})
+	return file_proto_airac_proto_rawDescData
+}
+
+var file_proto_airac_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_airac_proto_goTypes = []any{
+	(*AiracMessage)(nil),          // 0: proto.AiracMessage
+	(*AiracRange)(nil),            // 1: proto.AiracRange
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_proto_airac_proto_depIdxs = []int32{
+	2, // 0: proto.AiracMessage.effective:type_name -> google.protobuf.Timestamp
+	2, // 1: proto.AiracMessage.expires:type_name -> google.protobuf.Timestamp
+	0, // 2: proto.AiracRange.first:type_name -> proto.AiracMessage
+	0, // 3: proto.AiracRange.last:type_name -> proto.AiracMessage
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_proto_airac_proto_init() }
+func file_proto_airac_proto_init() {
+	if File_proto_airac_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_airac_proto_rawDesc), len(file_proto_airac_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_airac_proto_goTypes,
+		DependencyIndexes: file_proto_airac_proto_depIdxs,
+		MessageInfos:      file_proto_airac_proto_msgTypes,
+	}.Build()
+	File_proto_airac_proto = out.File
+	file_proto_airac_proto_goTypes = nil
+	file_proto_airac_proto_depIdxs = nil
+}
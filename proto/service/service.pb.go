@@ -0,0 +1,460 @@
+//
+// Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: proto/service/service.proto
+
+package service
+
+import (
+	proto "github.com/jwkohnen/airac/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AiracList is a list of consecutive cycles in chronological order.
+type AiracList struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Airacs []*proto.AiracMessage  `protobuf:"bytes,1,rep,name=airacs,proto3" json:"airacs,omitempty"`
+	// range spans the list; it is unset if the list is empty.
+	Range         *proto.AiracRange `protobuf:"bytes,2,opt,name=range,proto3" json:"range,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AiracList) Reset() {
+	*x = AiracList{}
+	mi := &file_proto_service_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AiracList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AiracList) ProtoMessage() {}
+
+func (x *AiracList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AiracList.ProtoReflect.Descriptor instead.
+func (*AiracList) Descriptor() ([]byte, []int) {
+	return file_proto_service_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AiracList) GetAiracs() []*proto.AiracMessage {
+	if x != nil {
+		return x.Airacs
+	}
+	return nil
+}
+
+func (x *AiracList) GetRange() *proto.AiracRange {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+type GetCurrentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrentRequest) Reset() {
+	*x = GetCurrentRequest{}
+	mi := &file_proto_service_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrentRequest) ProtoMessage() {}
+
+func (x *GetCurrentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrentRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_service_proto_rawDescGZIP(), []int{1}
+}
+
+type FromDateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// date is YYYY-MM-DD (UTC) or an RFC 3339 timestamp.
+	Date          string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FromDateRequest) Reset() {
+	*x = FromDateRequest{}
+	mi := &file_proto_service_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FromDateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FromDateRequest) ProtoMessage() {}
+
+func (x *FromDateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FromDateRequest.ProtoReflect.Descriptor instead.
+func (*FromDateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FromDateRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type FromIdentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ident is the identifier YYOO, e.g. "2101".
+	Ident         string `protobuf:"bytes,1,opt,name=ident,proto3" json:"ident,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FromIdentRequest) Reset() {
+	*x = FromIdentRequest{}
+	mi := &file_proto_service_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FromIdentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FromIdentRequest) ProtoMessage() {}
+
+func (x *FromIdentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FromIdentRequest.ProtoReflect.Descriptor instead.
+func (*FromIdentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FromIdentRequest) GetIdent() string {
+	if x != nil {
+		return x.Ident
+	}
+	return ""
+}
+
+type ListYearRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Year          int32                  `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListYearRequest) Reset() {
+	*x = ListYearRequest{}
+	mi := &file_proto_service_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListYearRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListYearRequest) ProtoMessage() {}
+
+func (x *ListYearRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListYearRequest.ProtoReflect.Descriptor instead.
+func (*ListYearRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListYearRequest) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+type ListRangeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// from and to are identifiers of the first and last cycle, inclusive.
+	From          string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRangeRequest) Reset() {
+	*x = ListRangeRequest{}
+	mi := &file_proto_service_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRangeRequest) ProtoMessage() {}
+
+func (x *ListRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRangeRequest.ProtoReflect.Descriptor instead.
+func (*ListRangeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListRangeRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ListRangeRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type WatchCycleChangesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// offset_seconds shifts each cycle change relative to the effective date;
+	// a negative offset notifies ahead of time.
+	OffsetSeconds int64 `protobuf:"zigzag64,1,opt,name=offset_seconds,json=offsetSeconds,proto3" json:"offset_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchCycleChangesRequest) Reset() {
+	*x = WatchCycleChangesRequest{}
+	mi := &file_proto_service_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchCycleChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCycleChangesRequest) ProtoMessage() {}
+
+func (x *WatchCycleChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCycleChangesRequest.ProtoReflect.Descriptor instead.
+func (*WatchCycleChangesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WatchCycleChangesRequest) GetOffsetSeconds() int64 {
+	if x != nil {
+		return x.OffsetSeconds
+	}
+	return 0
+}
+
+var File_proto_service_service_proto protoreflect.FileDescriptor
+
+const file_proto_service_service_proto_rawDesc = "" +
+	"\n" +
+	"\x1bproto/service/service.proto\x12\x05proto\x1a\x11proto/airac.proto\"a\n" +
+	"\tAiracList\x12+\n" +
+	"\x06airacs\x18\x01 \x03(\v2\x13.proto.AiracMessageR\x06airacs\x12'\n" +
+	"\x05range\x18\x02 \x01(\v2\x11.proto.AiracRangeR\x05range\"\x13\n" +
+	"\x11GetCurrentRequest\"%\n" +
+	"\x0fFromDateRequest\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\"(\n" +
+	"\x10FromIdentRequest\x12\x14\n" +
+	"\x05ident\x18\x01 \x01(\tR\x05ident\"%\n" +
+	"\x0fListYearRequest\x12\x12\n" +
+	"\x04year\x18\x01 \x01(\x05R\x04year\"6\n" +
+	"\x10ListRangeRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\"A\n" +
+	"\x18WatchCycleChangesRequest\x12%\n" +
+	"\x0eoffset_seconds\x18\x01 \x01(\x12R\roffsetSeconds2\xfa\x02\n" +
+	"\fAiracService\x12;\n" +
+	"\n" +
+	"GetCurrent\x12\x18.proto.GetCurrentRequest\x1a\x13.proto.AiracMessage\x127\n" +
+	"\bFromDate\x12\x16.proto.FromDateRequest\x1a\x13.proto.AiracMessage\x129\n" +
+	"\tFromIdent\x12\x17.proto.FromIdentRequest\x1a\x13.proto.AiracMessage\x124\n" +
+	"\bListYear\x12\x16.proto.ListYearRequest\x1a\x10.proto.AiracList\x126\n" +
+	"\tListRange\x12\x17.proto.ListRangeRequest\x1a\x10.proto.AiracList\x12K\n" +
+	"\x11WatchCycleChanges\x12\x1f.proto.WatchCycleChangesRequest\x1a\x13.proto.AiracMessage0\x01B)Z'github.com/jwkohnen/airac/proto/serviceb\x06proto3"
+
+var (
+	file_proto_service_service_proto_rawDescOnce sync.Once
+	file_proto_service_service_proto_rawDescData []byte
+)
+
+func file_proto_service_service_proto_rawDescGZIP() []byte {
+	file_proto_service_service_proto_rawDescOnce.Do(func() {
+		file_proto_service_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_service_service_proto_rawDesc), len(file_proto_service_service_proto_rawDesc)))
+	})
+	return file_proto_service_service_proto_rawDescData
+}
+
+var file_proto_service_service_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_service_service_proto_goTypes = []any{
+	(*AiracList)(nil),                // 0: proto.AiracList
+	(*GetCurrentRequest)(nil),        // 1: proto.GetCurrentRequest
+	(*FromDateRequest)(nil),          // 2: proto.FromDateRequest
+	(*FromIdentRequest)(nil),         // 3: proto.FromIdentRequest
+	(*ListYearRequest)(nil),          // 4: proto.ListYearRequest
+	(*ListRangeRequest)(nil),         // 5: proto.ListRangeRequest
+	(*WatchCycleChangesRequest)(nil), // 6: proto.WatchCycleChangesRequest
+	(*proto.AiracMessage)(nil),       // 7: proto.AiracMessage
+	(*proto.AiracRange)(nil),         // 8: proto.AiracRange
+}
+var file_proto_service_service_proto_depIdxs = []int32{
+	7, // 0: proto.AiracList.airacs:type_name -> proto.AiracMessage
+	8, // 1: proto.AiracList.range:type_name -> proto.AiracRange
+	1, // 2: proto.AiracService.GetCurrent:input_type -> proto.GetCurrentRequest
+	2, // 3: proto.AiracService.FromDate:input_type -> proto.FromDateRequest
+	3, // 4: proto.AiracService.FromIdent:input_type -> proto.FromIdentRequest
+	4, // 5: proto.AiracService.ListYear:input_type -> proto.ListYearRequest
+	5, // 6: proto.AiracService.ListRange:input_type -> proto.ListRangeRequest
+	6, // 7: proto.AiracService.WatchCycleChanges:input_type -> proto.WatchCycleChangesRequest
+	7, // 8: proto.AiracService.GetCurrent:output_type -> proto.AiracMessage
+	7, // 9: proto.AiracService.FromDate:output_type -> proto.AiracMessage
+	7, // 10: proto.AiracService.FromIdent:output_type -> proto.AiracMessage
+	0, // 11: proto.AiracService.ListYear:output_type -> proto.AiracList
+	0, // 12: proto.AiracService.ListRange:output_type -> proto.AiracList
+	7, // 13: proto.AiracService.WatchCycleChanges:output_type -> proto.AiracMessage
+	8, // [8:14] is the sub-list for method output_type
+	2, // [2:8] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_proto_service_service_proto_init() }
+func file_proto_service_service_proto_init() {
+	if File_proto_service_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_service_service_proto_rawDesc), len(file_proto_service_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_service_service_proto_goTypes,
+		DependencyIndexes: file_proto_service_service_proto_depIdxs,
+		MessageInfos:      file_proto_service_service_proto_msgTypes,
+	}.Build()
+	File_proto_service_service_proto = out.File
+	file_proto_service_service_proto_goTypes = nil
+	file_proto_service_service_proto_depIdxs = nil
+}
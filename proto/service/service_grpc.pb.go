@@ -0,0 +1,345 @@
+//
+// Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: proto/service/service.proto
+
+package service
+
+import (
+	context "context"
+	proto "github.com/jwkohnen/airac/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AiracService_GetCurrent_FullMethodName        = "/proto.AiracService/GetCurrent"
+	AiracService_FromDate_FullMethodName          = "/proto.AiracService/FromDate"
+	AiracService_FromIdent_FullMethodName         = "/proto.AiracService/FromIdent"
+	AiracService_ListYear_FullMethodName          = "/proto.AiracService/ListYear"
+	AiracService_ListRange_FullMethodName         = "/proto.AiracService/ListRange"
+	AiracService_WatchCycleChanges_FullMethodName = "/proto.AiracService/WatchCycleChanges"
+)
+
+// AiracServiceClient is the client API for AiracService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AiracServiceClient interface {
+	// GetCurrent returns the cycle effective now.
+	GetCurrent(ctx context.Context, in *GetCurrentRequest, opts ...grpc.CallOption) (*proto.AiracMessage, error)
+	// FromDate returns the cycle effective at a date.
+	FromDate(ctx context.Context, in *FromDateRequest, opts ...grpc.CallOption) (*proto.AiracMessage, error)
+	// FromIdent returns the cycle with an identifier.
+	FromIdent(ctx context.Context, in *FromIdentRequest, opts ...grpc.CallOption) (*proto.AiracMessage, error)
+	// ListYear returns all cycles of a year.
+	ListYear(ctx context.Context, in *ListYearRequest, opts ...grpc.CallOption) (*AiracList, error)
+	// ListRange returns a range of cycles.
+	ListRange(ctx context.Context, in *ListRangeRequest, opts ...grpc.CallOption) (*AiracList, error)
+	// WatchCycleChanges sends the current cycle, then each new cycle as it
+	// becomes effective.
+	WatchCycleChanges(ctx context.Context, in *WatchCycleChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[proto.AiracMessage], error)
+}
+
+type airacServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAiracServiceClient(cc grpc.ClientConnInterface) AiracServiceClient {
+	return &airacServiceClient{cc}
+}
+
+func (c *airacServiceClient) GetCurrent(ctx context.Context, in *GetCurrentRequest, opts ...grpc.CallOption) (*proto.AiracMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(proto.AiracMessage)
+	err := c.cc.Invoke(ctx, AiracService_GetCurrent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airacServiceClient) FromDate(ctx context.Context, in *FromDateRequest, opts ...grpc.CallOption) (*proto.AiracMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(proto.AiracMessage)
+	err := c.cc.Invoke(ctx, AiracService_FromDate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airacServiceClient) FromIdent(ctx context.Context, in *FromIdentRequest, opts ...grpc.CallOption) (*proto.AiracMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(proto.AiracMessage)
+	err := c.cc.Invoke(ctx, AiracService_FromIdent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airacServiceClient) ListYear(ctx context.Context, in *ListYearRequest, opts ...grpc.CallOption) (*AiracList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AiracList)
+	err := c.cc.Invoke(ctx, AiracService_ListYear_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airacServiceClient) ListRange(ctx context.Context, in *ListRangeRequest, opts ...grpc.CallOption) (*AiracList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AiracList)
+	err := c.cc.Invoke(ctx, AiracService_ListRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *airacServiceClient) WatchCycleChanges(ctx context.Context, in *WatchCycleChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[proto.AiracMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AiracService_ServiceDesc.Streams[0], AiracService_WatchCycleChanges_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchCycleChangesRequest, proto.AiracMessage]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AiracService_WatchCycleChangesClient = grpc.ServerStreamingClient[proto.AiracMessage]
+
+// AiracServiceServer is the server API for AiracService service.
+// All implementations must embed UnimplementedAiracServiceServer
+// for forward compatibility.
+type AiracServiceServer interface {
+	// GetCurrent returns the cycle effective now.
+	GetCurrent(context.Context, *GetCurrentRequest) (*proto.AiracMessage, error)
+	// FromDate returns the cycle effective at a date.
+	FromDate(context.Context, *FromDateRequest) (*proto.AiracMessage, error)
+	// FromIdent returns the cycle with an identifier.
+	FromIdent(context.Context, *FromIdentRequest) (*proto.AiracMessage, error)
+	// ListYear returns all cycles of a year.
+	ListYear(context.Context, *ListYearRequest) (*AiracList, error)
+	// ListRange returns a range of cycles.
+	ListRange(context.Context, *ListRangeRequest) (*AiracList, error)
+	// WatchCycleChanges sends the current cycle, then each new cycle as it
+	// becomes effective.
+	WatchCycleChanges(*WatchCycleChangesRequest, grpc.ServerStreamingServer[proto.AiracMessage]) error
+	mustEmbedUnimplementedAiracServiceServer()
+}
+
+// UnimplementedAiracServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAiracServiceServer struct{}
+
+func (UnimplementedAiracServiceServer) GetCurrent(context.Context, *GetCurrentRequest) (*proto.AiracMessage, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCurrent not implemented")
+}
+func (UnimplementedAiracServiceServer) FromDate(context.Context, *FromDateRequest) (*proto.AiracMessage, error) {
+	return nil, status.Error(codes.Unimplemented, "method FromDate not implemented")
+}
+func (UnimplementedAiracServiceServer) FromIdent(context.Context, *FromIdentRequest) (*proto.AiracMessage, error) {
+	return nil, status.Error(codes.Unimplemented, "method FromIdent not implemented")
+}
+func (UnimplementedAiracServiceServer) ListYear(context.Context, *ListYearRequest) (*AiracList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListYear not implemented")
+}
+func (UnimplementedAiracServiceServer) ListRange(context.Context, *ListRangeRequest) (*AiracList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListRange not implemented")
+}
+func (UnimplementedAiracServiceServer) WatchCycleChanges(*WatchCycleChangesRequest, grpc.ServerStreamingServer[proto.AiracMessage]) error {
+	return status.Error(codes.Unimplemented, "method WatchCycleChanges not implemented")
+}
+func (UnimplementedAiracServiceServer) mustEmbedUnimplementedAiracServiceServer() {}
+func (UnimplementedAiracServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAiracServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AiracServiceServer will
+// result in compilation errors.
+type UnsafeAiracServiceServer interface {
+	mustEmbedUnimplementedAiracServiceServer()
+}
+
+func RegisterAiracServiceServer(s grpc.ServiceRegistrar, srv AiracServiceServer) {
+	// If the following call panics, it indicates UnimplementedAiracServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AiracService_ServiceDesc, srv)
+}
+
+func _AiracService_GetCurrent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurrentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiracServiceServer).GetCurrent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiracService_GetCurrent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiracServiceServer).GetCurrent(ctx, req.(*GetCurrentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiracService_FromDate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FromDateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiracServiceServer).FromDate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiracService_FromDate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiracServiceServer).FromDate(ctx, req.(*FromDateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiracService_FromIdent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FromIdentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiracServiceServer).FromIdent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiracService_FromIdent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiracServiceServer).FromIdent(ctx, req.(*FromIdentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiracService_ListYear_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListYearRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiracServiceServer).ListYear(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiracService_ListYear_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiracServiceServer).ListYear(ctx, req.(*ListYearRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiracService_ListRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiracServiceServer).ListRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiracService_ListRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiracServiceServer).ListRange(ctx, req.(*ListRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiracService_WatchCycleChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCycleChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AiracServiceServer).WatchCycleChanges(m, &grpc.GenericServerStream[WatchCycleChangesRequest, proto.AiracMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AiracService_WatchCycleChangesServer = grpc.ServerStreamingServer[proto.AiracMessage]
+
+// AiracService_ServiceDesc is the grpc.ServiceDesc for AiracService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AiracService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.AiracService",
+	HandlerType: (*AiracServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCurrent",
+			Handler:    _AiracService_GetCurrent_Handler,
+		},
+		{
+			MethodName: "FromDate",
+			Handler:    _AiracService_FromDate_Handler,
+		},
+		{
+			MethodName: "FromIdent",
+			Handler:    _AiracService_FromIdent_Handler,
+		},
+		{
+			MethodName: "ListYear",
+			Handler:    _AiracService_ListYear_Handler,
+		},
+		{
+			MethodName: "ListRange",
+			Handler:    _AiracService_ListRange_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCycleChanges",
+			Handler:       _AiracService_WatchCycleChanges_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/service/service.proto",
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Range is a contiguous span of AIRAC cycles from First to Last inclusive. A
+// Range with Last before First is empty.
+type Range struct {
+	First AIRAC
+	Last  AIRAC
+}
+
+// Empty reports whether the range contains no cycles.
+func (r Range) Empty() bool { return r.Last < r.First }
+
+// Len returns the number of cycles in the range.
+func (r Range) Len() int {
+	if r.Empty() {
+		return 0
+	}
+	return int(r.Last) - int(r.First) + 1
+}
+
+// Contains reports whether cycle a is within the range.
+func (r Range) Contains(a AIRAC) bool { return r.First <= a && a <= r.Last }
+
+// Cycles returns all cycles of the range in chronological order.
+func (r Range) Cycles() []AIRAC {
+	cycles := make([]AIRAC, 0, r.Len())
+	for a := r.First; r.Contains(a); a++ {
+		cycles = append(cycles, a)
+		if a == r.Last {
+			break
+		}
+	}
+	return cycles
+}
+
+// Effective returns the effective date of the first cycle of the range.
+func (r Range) Effective() time.Time { return r.First.Effective() }
+
+// Expires returns the instant the range expires, i.e. the effective date of the
+// cycle following the last cycle of the range.
+func (r Range) Expires() time.Time { return (r.Last + 1).Effective() }
+
+// String returns the identifiers of the first and last cycle separated by a
+// dash, e.g. "2101-2106", or a single identifier if both are equal. An empty
+// range returns an empty string.
+func (r Range) String() string {
+	switch {
+	case r.Empty():
+		return ""
+	case r.First == r.Last:
+		return r.First.String()
+	default:
+		return r.First.String() + "-" + r.Last.String()
+	}
+}
+
+// ParseRange parses a range of two identifiers in the form "2101-2106" or
+// "2101..2106", or a single identifier that is a range of one cycle. See
+// FromString for the interpretation of the identifiers.
+func ParseRange(s string) (Range, error) {
+	first, last := s, s
+	if i := strings.Index(s, ".."); i >= 0 {
+		first, last = s[:i], s[i+2:]
+	} else if i := strings.LastIndex(s, "-"); i > 0 {
+		first, last = s[:i], s[i+1:]
+	}
+
+	f, err := FromString(first)
+	if err != nil {
+		return Range{}, fmt.Errorf("illegal AIRAC range %q: %w", s, err)
+	}
+
+	l, err := FromString(last)
+	if err != nil {
+		return Range{}, fmt.Errorf("illegal AIRAC range %q: %w", s, err)
+	}
+
+	if l < f {
+		return Range{}, fmt.Errorf("illegal AIRAC range %q: %s is before %s", s, last, first)
+	}
+
+	return Range{First: f, Last: l}, nil
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		in    string
+		want  string
+		len   int
+		valid bool
+	}{
+		{"2101-2106", "2101-2106", 6, true},
+		{"2101..2106", "2101-2106", 6, true},
+		{"2013..2102", "2013-2102", 4, true},
+		{"2101", "2101", 1, true},
+		{"2106-2101", "", 0, false},
+		{"2101-", "", 0, false},
+		{"-2101", "", 0, false},
+		{"2101..21x6", "", 0, false},
+		{"", "", 0, false},
+	}
+
+	for _, tt := range testt {
+		got, err := ParseRange(tt.in)
+		if tt.valid != (err == nil) {
+			t.Errorf("%q: want valid %t, got error %v", tt.in, tt.valid, err)
+			continue
+		}
+		if !tt.valid {
+			continue
+		}
+		if got.String() != tt.want || got.Len() != tt.len {
+			t.Errorf("%q: want %s (%d cycles), got %s (%d cycles)", tt.in, tt.want, tt.len, got, got.Len())
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+
+	r := Range{First: FromStringMust("2013"), Last: FromStringMust("2101")}
+
+	want := []AIRAC{FromStringMust("2013"), FromStringMust("2014"), FromStringMust("2101")}
+	if got := r.Cycles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if r.Contains(FromStringMust("2012")) || !r.Contains(FromStringMust("2014")) || r.Contains(FromStringMust("2102")) {
+		t.Error("Contains is wrong")
+	}
+
+	if got, want := r.Effective().Format(format), "2020-12-03"; got != want {
+		t.Errorf("effective: want %s, got %s", want, got)
+	}
+	if got, want := r.Expires().Format(format), "2021-02-25"; got != want {
+		t.Errorf("expires: want %s, got %s", want, got)
+	}
+
+	empty := Range{First: r.Last, Last: r.First}
+	if !empty.Empty() || empty.Len() != 0 || len(empty.Cycles()) != 0 || empty.String() != "" {
+		t.Errorf("empty range %#v is not empty", empty)
+	}
+}
+
+func ExampleParseRange() {
+	r, err := ParseRange("2012..2102")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(r, r.Len(), r.Cycles())
+
+	// Output:
+	// 2012-2102 5 [2012 2013 2014 2101 2102]
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package reference ships a machine-readable transcription of published AIRAC
+// schedules with their source citations and verifies the effective dates
+// computed by package airac against it.
+//
+// The dataset transcribes ICAO DOC 8126, 6th edition, Table 2-1 and
+// paragraph 2.6.2 b), and two EUROCONTROL tables of AIRAC dates. Copies of the
+// tables are in the tables directory of the repository. Each entry records the
+// date as printed and where it is printed, so that every verified date can be
+// traced back to its source.
+//
+// Dates that are obviously misprinted in a source are transcribed as printed
+// and marked as errata. Verify expects the computed date to differ from them.
+package reference
+
+import (
+	"bytes"
+	_ "embed" // for the dataset
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jwkohnen/airac"
+)
+
+//go:embed reference.json
+var dataset []byte
+
+const dateFormat = "2006-01-02"
+
+// Source is a publication that entries are transcribed from.
+type Source struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Publisher string `json:"publisher"`
+	Edition   string `json:"edition,omitempty"`
+	URL       string `json:"url,omitempty"`
+
+	// Retrieved is the date a web page was retrieved, YYYY-MM-DD.
+	Retrieved string `json:"retrieved,omitempty"`
+
+	// Document is the path of a copy of the source in the repository.
+	Document string `json:"document,omitempty"`
+
+	// Quote is the cited text of the source, if it is prose.
+	Quote string `json:"quote,omitempty"`
+}
+
+// Entry is a published AIRAC effective date.
+type Entry struct {
+	// Ident is the cycle identifier. It is empty if the source only states
+	// that the date is an effective date.
+	Ident string `json:"ident,omitempty"`
+
+	// Effective is the published date, YYYY-MM-DD.
+	Effective string `json:"effective"`
+
+	// Printed is the date as printed in the source.
+	Printed string `json:"printed"`
+
+	// Source is the ID of the Source.
+	Source string `json:"source"`
+
+	// Location is the position of the date in the source, e.g. "column 2003,
+	// row 1".
+	Location string `json:"location"`
+
+	// Erratum explains why the printed date is a misprint. It is empty for
+	// correct dates.
+	Erratum string `json:"erratum,omitempty"`
+}
+
+// Dataset is the reference dataset.
+type Dataset struct {
+	Sources []Source `json:"sources"`
+	Entries []Entry  `json:"entries"`
+}
+
+// Load returns the embedded reference dataset.
+func Load() (Dataset, error) {
+	var d Dataset
+
+	dec := json.NewDecoder(bytes.NewReader(dataset))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&d); err != nil {
+		return d, fmt.Errorf("reference dataset: %w", err)
+	}
+
+	return d, d.validate()
+}
+
+// Source returns the source with the given ID.
+func (d Dataset) Source(id string) (Source, bool) {
+	for _, s := range d.Sources {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Source{}, false
+}
+
+func (d Dataset) validate() error {
+	for i, e := range d.Entries {
+		if _, ok := d.Source(e.Source); !ok {
+			return fmt.Errorf("reference dataset: entry %d: unknown source %q", i, e.Source)
+		}
+		if _, err := time.Parse(dateFormat, e.Effective); err != nil {
+			return fmt.Errorf("reference dataset: entry %d: %w", i, err)
+		}
+		if e.Ident != "" {
+			if _, err := airac.FromString(e.Ident); err != nil {
+				return fmt.Errorf("reference dataset: entry %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Result is the outcome of verifying one entry.
+type Result struct {
+	Entry
+
+	// Computed is the effective date computed by package airac, YYYY-MM-DD.
+	// For entries without Ident it is the effective date of the cycle
+	// effective at the published date.
+	Computed string `json:"computed"`
+}
+
+// Match reports whether the computed date equals the published date.
+func (r Result) Match() bool { return r.Computed == r.Effective }
+
+// Divergent reports whether the result contradicts the source: a correct
+// date that does not match, or an erratum that does.
+func (r Result) Divergent() bool { return r.Match() == (r.Erratum != "") }
+
+// Report is the outcome of Verify.
+type Report struct {
+	// Results holds one result per entry, in the order of the dataset.
+	Results []Result `json:"results"`
+}
+
+// OK reports whether no result is divergent.
+func (r Report) OK() bool { return len(r.Divergences()) == 0 }
+
+// Divergences returns the divergent results.
+func (r Report) Divergences() []Result {
+	var div []Result
+	for _, res := range r.Results {
+		if res.Divergent() {
+			div = append(div, res)
+		}
+	}
+	return div
+}
+
+// Errata returns the results of entries marked as errata.
+func (r Report) Errata() []Result {
+	var errata []Result
+	for _, res := range r.Results {
+		if res.Erratum != "" {
+			errata = append(errata, res)
+		}
+	}
+	return errata
+}
+
+// Verify compares the computed effective date of each entry of the dataset
+// with its published date.
+func (d Dataset) Verify() Report {
+	r := Report{Results: make([]Result, 0, len(d.Entries))}
+
+	for _, e := range d.Entries {
+		published, _ := time.Parse(dateFormat, e.Effective) // validated by Load
+
+		a := airac.FromDate(published)
+		if e.Ident != "" {
+			a, _ = airac.FromString(e.Ident) // validated by Load
+		}
+
+		r.Results = append(r.Results, Result{Entry: e, Computed: a.Effective().Format(dateFormat)})
+	}
+
+	return r
+}
+
+// Verify loads the embedded dataset and verifies it.
+func Verify() (Dataset, Report, error) {
+	d, err := Load()
+	if err != nil {
+		return d, Report{}, err
+	}
+	return d, d.Verify(), nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reference
+
+import (
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	d, r, err := Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, res := range r.Divergences() {
+		t.Errorf("%s %s (%s): published %s, computed %s", res.Source, res.Location, res.Ident, res.Effective, res.Computed)
+	}
+
+	if got, want := len(r.Results), len(d.Entries); got != want {
+		t.Errorf("want %d results, got %d", want, got)
+	}
+	if got, want := len(r.Errata()), 2; got != want {
+		t.Errorf("want %d errata, got %d", want, got)
+	}
+	if !r.OK() {
+		t.Error("want OK report")
+	}
+}
+
+func TestDatasetCoverage(t *testing.T) {
+	t.Parallel()
+
+	d, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := make(map[string]int)
+	for _, e := range d.Entries {
+		count[e.Source]++
+	}
+
+	testt := []struct {
+		source string
+		want   int
+	}{
+		{"icao-8126-2.6.2", 1},
+		{"icao-8126-table-2-1", 10 * 13},
+		{"eurocontrol-adherence", 11*13 + 1},
+		{"eurocontrol-nm-airac-dates", 14 + 3*13},
+	}
+
+	for _, tt := range testt {
+		if got := count[tt.source]; got != tt.want {
+			t.Errorf("%s: want %d entries, got %d", tt.source, tt.want, got)
+		}
+	}
+	if got, want := len(d.Sources), len(testt); got != want {
+		t.Errorf("want %d sources, got %d", want, got)
+	}
+}
+
+func TestDivergent(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		res  Result
+		want bool
+	}{
+		{Result{Entry: Entry{Effective: "2021-01-28"}, Computed: "2021-01-28"}, false},
+		{Result{Entry: Entry{Effective: "2021-01-29"}, Computed: "2021-01-28"}, true},
+		{Result{Entry: Entry{Effective: "2021-01-29", Erratum: "misprint"}, Computed: "2021-01-28"}, false},
+		{Result{Entry: Entry{Effective: "2021-01-28", Erratum: "misprint"}, Computed: "2021-01-28"}, true},
+	}
+
+	for _, tt := range testt {
+		if got := tt.res.Divergent(); got != tt.want {
+			t.Errorf("%+v: want %t, got %t", tt.res, tt.want, got)
+		}
+	}
+
+	r := Report{Results: []Result{testt[0].res, testt[1].res}}
+	if r.OK() {
+		t.Error("want report not OK")
+	}
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package retention decides which cycles of a per-cycle archive to keep and
+// which to prune, e.g. "keep the last 6 cycles, the first cycle of each year
+// for 10 years, and anything referenced by an open investigation":
+//
+//	p := retention.Policy{
+//		retention.Last(6),
+//		retention.FirstOfYear(10),
+//		retention.Hold("investigation 2021-17", investigated),
+//	}
+//	plan := p.Apply(airac.FromDate(time.Now()), onDisk)
+//
+// A Plan records the decision and the reasons for each cycle. Report writes
+// it as a dry run; Execute prunes the cycles.
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jwkohnen/airac"
+)
+
+// Rule decides whether to keep a cycle.
+type Rule interface {
+	// Keep reports whether to keep cycle a when current is the current
+	// cycle, and if so, why.
+	Keep(a, current airac.AIRAC) (reason string, ok bool)
+}
+
+type ruleFunc struct {
+	reason func(a, current airac.AIRAC) string
+	keep   func(a, current airac.AIRAC) bool
+}
+
+func (r ruleFunc) Keep(a, current airac.AIRAC) (string, bool) {
+	if !r.keep(a, current) {
+		return "", false
+	}
+	return r.reason(a, current), true
+}
+
+// Func returns a rule that keeps the cycles for which keep returns true, for
+// the given reason. Use it for rules in terms of AIRAC.Year and
+// AIRAC.Ordinal that this package does not provide, e.g. keeping the middle
+// cycle of each year:
+//
+//	retention.Func("mid-year cycle", func(a, _ airac.AIRAC) bool { return a.Ordinal() == 7 })
+func Func(reason string, keep func(a, current airac.AIRAC) bool) Rule {
+	return ruleFunc{
+		reason: func(_, _ airac.AIRAC) string { return reason },
+		keep:   keep,
+	}
+}
+
+// Last returns a rule that keeps the current cycle and the n-1 cycles before
+// it.
+func Last(n int) Rule {
+	return ruleFunc{
+		reason: func(_, _ airac.AIRAC) string { return fmt.Sprintf("last %d cycles", n) },
+		keep: func(a, current airac.AIRAC) bool {
+			return a <= current && int(current)-int(a) < n
+		},
+	}
+}
+
+// FirstOfYear returns a rule that keeps the first cycle of the year of the
+// current cycle and of the years-1 years before it.
+func FirstOfYear(years int) Rule {
+	return ruleFunc{
+		reason: func(a, _ airac.AIRAC) string {
+			return fmt.Sprintf("first cycle of %d, kept for %d years", a.Year(), years)
+		},
+		keep: func(a, current airac.AIRAC) bool {
+			age := current.Year() - a.Year()
+			return a.Ordinal() == 1 && a <= current && age < years
+		},
+	}
+}
+
+// Hold returns a rule that keeps the cycles of set for the given reason, e.g.
+// the cycles referenced by an open investigation.
+func Hold(reason string, set airac.Set) Rule {
+	return Func(reason, func(a, _ airac.AIRAC) bool { return set.Contains(a) })
+}
+
+// Policy is a list of rules. A cycle before the current cycle is kept if any
+// rule keeps it and pruned otherwise. The current cycle and the cycles after
+// it, i.e. datasets distributed ahead of their effective date, are always
+// kept, even by an empty policy.
+type Policy []Rule
+
+// Keep returns the reasons of all rules that keep cycle a when current is the
+// current cycle. It returns none if a is to be pruned.
+func (p Policy) Keep(a, current airac.AIRAC) []string {
+	var reasons []string
+	for _, r := range p {
+		if reason, ok := r.Keep(a, current); ok {
+			reasons = append(reasons, reason)
+		}
+	}
+	return reasons
+}
+
+// Apply decides for each of the cycles whether to keep or prune it when
+// current is the current cycle. The current cycle is kept for the reason
+// "current" and the cycles after it for "not yet effective", in addition to
+// the reasons of the rules.
+func (p Policy) Apply(current airac.AIRAC, cycles airac.Set) Plan {
+	plan := Plan{Current: current, Decisions: make([]Decision, 0, cycles.Len())}
+	for a := range cycles.All() {
+		var reasons []string
+		switch {
+		case a == current:
+			reasons = append(reasons, "current")
+		case a > current:
+			reasons = append(reasons, "not yet effective")
+		}
+		reasons = append(reasons, p.Keep(a, current)...)
+		plan.Decisions = append(plan.Decisions, Decision{Cycle: a, Keep: len(reasons) > 0, Reasons: reasons})
+	}
+	return plan
+}
+
+// Decision is the verdict of a policy on a cycle.
+type Decision struct {
+	Cycle airac.AIRAC
+	Keep  bool
+
+	// Reasons are the reasons of the rules that keep the cycle.
+	Reasons []string
+}
+
+// String returns the decision and its reasons, e.g.
+// "keep  2101  last 6 cycles; first cycle of 2021, kept for 10 years".
+func (d Decision) String() string {
+	if !d.Keep {
+		return "prune " + d.Cycle.String()
+	}
+	return fmt.Sprintf("keep  %s  %s", d.Cycle, strings.Join(d.Reasons, "; "))
+}
+
+// Plan is the outcome of applying a policy to the cycles of an archive.
+type Plan struct {
+	Current airac.AIRAC
+
+	// Decisions are in chronological order.
+	Decisions []Decision
+}
+
+// Kept returns the cycles to keep.
+func (p Plan) Kept() airac.Set { return p.set(true) }
+
+// Pruned returns the cycles to prune.
+func (p Plan) Pruned() airac.Set { return p.set(false) }
+
+func (p Plan) set(keep bool) airac.Set {
+	var s airac.Set
+	for _, d := range p.Decisions {
+		if d.Keep == keep {
+			s.Add(d.Cycle)
+		}
+	}
+	return s
+}
+
+// Report writes the plan as a dry run, one decision per line, followed by a
+// summary.
+func (p Plan) Report(w io.Writer) error {
+	fmt.Fprintf(w, "current cycle: %s\n", p.Current)
+	for _, d := range p.Decisions {
+		fmt.Fprintln(w, d)
+	}
+
+	kept := p.Kept()
+	_, err := fmt.Fprintf(w, "keep: %d (%s), prune: %d (%s)\n", kept.Len(), kept, len(p.Decisions)-kept.Len(), p.Pruned())
+	return err
+}
+
+// Execute calls prune for each cycle to prune, in chronological order. It
+// continues after errors and returns all of them joined.
+func (p Plan) Execute(prune func(airac.AIRAC) error) error {
+	var errs []error
+	for _, d := range p.Decisions {
+		if d.Keep {
+			continue
+		}
+		if err := prune(d.Cycle); err != nil {
+			errs = append(errs, fmt.Errorf("prune %s: %w", d.Cycle, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jwkohnen/airac"
+)
+
+func set(t *testing.T, s string) airac.Set {
+	t.Helper()
+	set, err := airac.ParseSet(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return set
+}
+
+func ident(t *testing.T, s string) airac.AIRAC {
+	t.Helper()
+	a, err := airac.FromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestRules(t *testing.T) {
+	t.Parallel()
+
+	current := ident(t, "2104")
+
+	testt := []struct {
+		name string
+		rule Rule
+		keep string
+	}{
+		{"last 3", Last(3), "2102-2104"},
+		{"last 0", Last(0), ""},
+		{"first of year 2", FirstOfYear(2), "2001,2101"},
+		{"hold", Hold("investigation", set(t, "1907,2010")), "1907,2010"},
+		{"func", Func("mid-year", func(a, _ airac.AIRAC) bool { return a.Ordinal() == 7 }), "1907,2007"},
+	}
+
+	cycles := set(t, "1901-2106")
+	for _, tt := range testt {
+		var kept airac.Set
+		for a := range cycles.All() {
+			if _, ok := tt.rule.Keep(a, current); ok {
+				kept.Add(a)
+			}
+		}
+		if kept.String() != tt.keep {
+			t.Errorf("%s: want %q, got %q", tt.name, tt.keep, kept)
+		}
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+
+	p := Policy{
+		Last(3),
+		FirstOfYear(10),
+		Hold("investigation 2021-17", set(t, "2006,2101")),
+	}
+	plan := p.Apply(ident(t, "2104"), set(t, "1912-1913,2001-2007,2101-2105"))
+
+	if got := plan.Kept().String(); got != "2001,2006,2101-2105" {
+		t.Errorf("want kept 2001,2006,2101-2105, got %s", got)
+	}
+	if got := plan.Pruned().String(); got != "1912-1913,2002-2005,2007" {
+		t.Errorf("want pruned 1912-1913,2002-2005,2007, got %s", got)
+	}
+
+	var buf bytes.Buffer
+	if err := plan.Report(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "current cycle: 2104\n" +
+		"prune 1912\n" +
+		"prune 1913\n" +
+		"keep  2001  first cycle of 2020, kept for 10 years\n" +
+		"prune 2002\n" +
+		"prune 2003\n" +
+		"prune 2004\n" +
+		"prune 2005\n" +
+		"keep  2006  investigation 2021-17\n" +
+		"prune 2007\n" +
+		"keep  2101  first cycle of 2021, kept for 10 years; investigation 2021-17\n" +
+		"keep  2102  last 3 cycles\n" +
+		"keep  2103  last 3 cycles\n" +
+		"keep  2104  current; last 3 cycles\n" +
+		"keep  2105  not yet effective\n" +
+		"keep: 7 (2001,2006,2101-2105), prune: 7 (1912-1913,2002-2005,2007)\n"
+	if buf.String() != want {
+		t.Errorf("want\n%s\ngot\n%s", want, buf.String())
+	}
+}
+
+func TestEmptyPolicy(t *testing.T) {
+	t.Parallel()
+
+	plan := Policy{}.Apply(ident(t, "2104"), set(t, "2101-2106"))
+
+	if got := plan.Pruned().String(); got != "2101-2103" {
+		t.Errorf("want pruned 2101-2103, got %s", got)
+	}
+
+	want := []string{"keep  2104  current", "keep  2105  not yet effective", "keep  2106  not yet effective"}
+	for i, d := range plan.Decisions[3:] {
+		if got := d.String(); got != want[i] {
+			t.Errorf("want %q, got %q", want[i], got)
+		}
+	}
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	plan := Policy{Last(1)}.Apply(ident(t, "2104"), set(t, "2101-2104"))
+
+	var pruned airac.Set
+	errFail := errors.New("fail")
+	err := plan.Execute(func(a airac.AIRAC) error {
+		if a == ident(t, "2102") {
+			return errFail
+		}
+		pruned.Add(a)
+		return nil
+	})
+
+	if !errors.Is(err, errFail) || err.Error() != "prune 2102: fail" {
+		t.Errorf("want prune 2102: fail, got %v", err)
+	}
+	if pruned.String() != "2101,2103" {
+		t.Errorf("want 2101,2103 pruned, got %s", pruned)
+	}
+}
@@ -0,0 +1,375 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package schedule writes tables of AIRAC effective dates for a span of years
+// in the layout of ICAO DOC 8126, Table 2-1, and of the EUROCONTROL AIRAC
+// dates: one column per year and one row per cycle of the year.
+//
+// The table can be written as plain text, Markdown, HTML or CSV, optionally
+// with columns for the milestones of each cycle.
+package schedule
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jwkohnen/airac"
+)
+
+// Format is the output format of a table.
+type Format int
+
+const (
+	// Text is plain text with aligned columns.
+	Text Format = iota
+
+	// Markdown is a GitHub Flavored Markdown table.
+	Markdown
+
+	// HTML is an HTML table element.
+	HTML
+
+	// CSV is comma-separated values with a single header row.
+	CSV
+)
+
+// DefaultLayout is the layout of dates if Options.Layout is empty, e.g.
+// "28 Jan".
+const DefaultLayout = "2 Jan"
+
+// ParseFormat returns the Format named text, markdown, html or csv.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "text":
+		return Text, nil
+	case "markdown", "md":
+		return Markdown, nil
+	case "html":
+		return HTML, nil
+	case "csv":
+		return CSV, nil
+	default:
+		return 0, fmt.Errorf("unknown format %q, want text, markdown, html or csv", s)
+	}
+}
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case Text:
+		return "text"
+	case Markdown:
+		return "markdown"
+	case HTML:
+		return "html"
+	case CSV:
+		return "csv"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// Options configures a table.
+type Options struct {
+	// Format is the output format.
+	Format Format
+
+	// Milestones adds a column for each milestone next to the effective
+	// dates of each year.
+	Milestones bool
+
+	// Layout is the Go time layout of the dates. If empty, DefaultLayout is
+	// used. Milestone dates that fall into another year than the cycle, e.g.
+	// the publication of the first cycle of a year, get the year appended if
+	// the layout does not contain it.
+	Layout string
+
+	// Title is the caption of the table. If empty, a title like "Schedule
+	// of AIRAC effective dates, 2003-2012" is used. CSV has no title.
+	Title string
+}
+
+// table is the output independent form of a schedule: one group of columns per
+// year and one row per ordinal.
+type table struct {
+	title  string
+	groups []group
+	rows   []row
+}
+
+type group struct {
+	name string
+	cols []string
+}
+
+type row struct {
+	ordinal int
+	cells   []string
+}
+
+// Write writes the schedule of the years first to last, inclusive, to w.
+func Write(w io.Writer, first, last int, opts Options) error {
+	t, err := build(first, last, opts)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+
+	switch opts.Format {
+	case Text:
+		t.text(bw)
+	case Markdown:
+		t.markdown(bw)
+	case HTML:
+		t.html(bw)
+	case CSV:
+		if err := t.csv(bw); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %v", opts.Format)
+	}
+
+	return bw.Flush()
+}
+
+func build(first, last int, opts Options) (table, error) {
+	if first < 1902 || last > 2192 || first > last {
+		return table{}, fmt.Errorf("illegal span of years %d to %d, want 1902 to 2192", first, last)
+	}
+
+	layout := opts.Layout
+	if layout == "" {
+		layout = DefaultLayout
+	}
+	withYear := strings.Contains(layout, "2006") || strings.Contains(layout, "06")
+
+	t := table{title: opts.Title}
+	if t.title == "" {
+		t.title = fmt.Sprintf("Schedule of AIRAC effective dates, %d", first)
+		if last != first {
+			t.title += fmt.Sprintf("-%d", last)
+		}
+	}
+
+	cols := []string{"effective"}
+	if opts.Milestones {
+		for _, m := range airac.Milestones() {
+			cols = append(cols, m.String())
+		}
+	}
+
+	rows := 13
+	for year := first; year <= last; year++ {
+		t.groups = append(t.groups, group{name: strconv.Itoa(year), cols: cols})
+		if n := airac.CyclesInYear(year).Len(); n > rows {
+			rows = n
+		}
+	}
+
+	for ordinal := 1; ordinal <= rows; ordinal++ {
+		r := row{ordinal: ordinal}
+		for year := first; year <= last; year++ {
+			cycles := airac.CyclesInYear(year)
+			if ordinal > cycles.Len() {
+				r.cells = append(r.cells, make([]string, len(cols))...)
+				continue
+			}
+
+			a := cycles.First + airac.AIRAC(ordinal-1)
+			r.cells = append(r.cells, a.Effective().Format(layout))
+			if !opts.Milestones {
+				continue
+			}
+			for _, m := range airac.Milestones() {
+				date := a.Milestone(m)
+				cell := date.Format(layout)
+				if date.Year() != year && !withYear {
+					cell += date.Format(" 2006")
+				}
+				r.cells = append(r.cells, cell)
+			}
+		}
+		t.rows = append(t.rows, r)
+	}
+
+	return t, nil
+}
+
+// header returns a single header row. Columns of years with milestones are
+// named like "2021 publication".
+func (t table) header() []string {
+	h := []string{"#"}
+	for _, g := range t.groups {
+		for _, c := range g.cols {
+			if len(g.cols) == 1 {
+				h = append(h, g.name)
+			} else {
+				h = append(h, g.name+" "+c)
+			}
+		}
+	}
+	return h
+}
+
+func (t table) body() [][]string {
+	body := make([][]string, 0, len(t.rows))
+	for _, r := range t.rows {
+		body = append(body, append([]string{strconv.Itoa(r.ordinal)}, r.cells...))
+	}
+	return body
+}
+
+func (t table) text(w *bufio.Writer) {
+	var headers [][]string
+	if len(t.groups[0].cols) == 1 {
+		headers = [][]string{t.header()}
+	} else {
+		years, cols := []string{""}, []string{"#"}
+		for _, g := range t.groups {
+			years = append(years, g.name)
+			years = append(years, make([]string, len(g.cols)-1)...)
+			cols = append(cols, g.cols...)
+		}
+		headers = [][]string{years, cols}
+	}
+	body := t.body()
+
+	widths := make([]int, len(body[0]))
+	for _, r := range append(headers, body...) {
+		for i, c := range r {
+			if n := utf8.RuneCountInString(c); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	line := func(r []string) {
+		var b strings.Builder
+		for i, c := range r {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c))
+			if i == 0 {
+				b.WriteString(pad + c) // ordinals are right-aligned
+			} else {
+				b.WriteString(c + pad)
+			}
+		}
+		w.WriteString(strings.TrimRight(b.String(), " ") + "\n")
+	}
+
+	w.WriteString(t.title + "\n\n")
+	for _, h := range headers {
+		line(h)
+	}
+	rule := make([]string, len(widths))
+	for i, n := range widths {
+		rule[i] = strings.Repeat("-", n)
+	}
+	line(rule)
+	for _, r := range body {
+		line(r)
+	}
+}
+
+func (t table) markdown(w *bufio.Writer) {
+	escape := strings.NewReplacer("|", `\|`).Replace
+
+	line := func(r []string) {
+		w.WriteString("|")
+		for _, c := range r {
+			w.WriteString(" " + escape(c) + " |")
+		}
+		w.WriteString("\n")
+	}
+
+	w.WriteString("**" + escape(t.title) + "**\n\n")
+
+	header := t.header()
+	line(header)
+	w.WriteString("|")
+	for i := range header {
+		if i == 0 {
+			w.WriteString("--:|")
+		} else {
+			w.WriteString("---|")
+		}
+	}
+	w.WriteString("\n")
+
+	for _, r := range t.body() {
+		line(r)
+	}
+}
+
+func (t table) html(w *bufio.Writer) {
+	esc := html.EscapeString
+	milestones := len(t.groups[0].cols) > 1
+
+	w.WriteString("<table>\n")
+	w.WriteString("<caption>" + esc(t.title) + "</caption>\n")
+	w.WriteString("<thead>\n")
+
+	if milestones {
+		w.WriteString(`<tr><th rowspan="2" scope="col">#</th>`)
+		for _, g := range t.groups {
+			fmt.Fprintf(w, `<th colspan="%d" scope="colgroup">%s</th>`, len(g.cols), esc(g.name))
+		}
+		w.WriteString("</tr>\n<tr>")
+		for _, g := range t.groups {
+			for _, c := range g.cols {
+				w.WriteString(`<th scope="col">` + esc(c) + "</th>")
+			}
+		}
+		w.WriteString("</tr>\n")
+	} else {
+		w.WriteString("<tr>")
+		for _, h := range t.header() {
+			w.WriteString(`<th scope="col">` + esc(h) + "</th>")
+		}
+		w.WriteString("</tr>\n")
+	}
+
+	w.WriteString("</thead>\n<tbody>\n")
+	for _, r := range t.body() {
+		w.WriteString(`<tr><th scope="row">` + esc(r[0]) + "</th>")
+		for _, c := range r[1:] {
+			w.WriteString("<td>" + esc(c) + "</td>")
+		}
+		w.WriteString("</tr>\n")
+	}
+	w.WriteString("</tbody>\n</table>\n")
+}
+
+func (t table) csv(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.header()); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.body()); err != nil {
+		return err
+	}
+	return cw.Error()
+}
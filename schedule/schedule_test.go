@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schedule
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := Write(&buf, 2019, 2020, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Schedule of AIRAC effective dates, 2019-2020
+
+ #  2019    2020
+--  ------  ------
+ 1  3 Jan   2 Jan
+ 2  31 Jan  30 Jan
+ 3  28 Feb  27 Feb
+ 4  28 Mar  26 Mar
+ 5  25 Apr  23 Apr
+ 6  23 May  21 May
+ 7  20 Jun  18 Jun
+ 8  18 Jul  16 Jul
+ 9  15 Aug  13 Aug
+10  12 Sep  10 Sep
+11  10 Oct  8 Oct
+12  7 Nov   5 Nov
+13  5 Dec   3 Dec
+14          31 Dec
+`
+	if got := buf.String(); got != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+// TestWriteICAO compares with the first row and the last column of ICAO DOC
+// 8126, Table 2-1.
+func TestWriteICAO(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := Write(&buf, 2003, 2012, Options{Format: CSV, Layout: "2 January"}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	testt := []struct {
+		line int
+		want string
+	}{
+		{0, "#,2003,2004,2005,2006,2007,2008,2009,2010,2011,2012"},
+		{1, "1,23 January,22 January,20 January,19 January,18 January,17 January,15 January,14 January,13 January,12 January"},
+		{13, "13,25 December,23 December,22 December,21 December,20 December,18 December,17 December,16 December,15 December,13 December"},
+	}
+
+	if len(lines) != 14 {
+		t.Fatalf("want 14 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	for _, tt := range testt {
+		if got := lines[tt.line]; got != tt.want {
+			t.Errorf("line %d: want %s, got %s", tt.line, tt.want, got)
+		}
+	}
+}
+
+func TestWriteMilestones(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		format Format
+		want   []string
+	}{
+		{CSV, []string{
+			"#,2021 effective,2021 submission cut-off,2021 publication,2021 reception\n",
+			"1,28 Jan,3 Dec 2020,17 Dec 2020,31 Dec 2020\n",
+			"2,25 Feb,31 Dec 2020,14 Jan,28 Jan\n",
+		}},
+		{Markdown, []string{
+			"**Schedule of AIRAC effective dates, 2021**\n\n",
+			"| # | 2021 effective | 2021 submission cut-off | 2021 publication | 2021 reception |\n|--:|---|---|---|---|\n",
+			"| 13 | 30 Dec | 4 Nov | 18 Nov | 2 Dec |\n",
+		}},
+		{HTML, []string{
+			"<caption>Schedule of AIRAC effective dates, 2021</caption>",
+			`<tr><th rowspan="2" scope="col">#</th><th colspan="4" scope="colgroup">2021</th></tr>`,
+			`<tr><th scope="row">1</th><td>28 Jan</td><td>3 Dec 2020</td><td>17 Dec 2020</td><td>31 Dec 2020</td></tr>`,
+		}},
+		{Text, []string{
+			"    2021\n #  effective  submission cut-off  publication  reception\n",
+			" 1  28 Jan     3 Dec 2020          17 Dec 2020  31 Dec 2020\n",
+		}},
+	}
+
+	for _, tt := range testt {
+		var buf bytes.Buffer
+		if err := Write(&buf, 2021, 2021, Options{Format: tt.format, Milestones: true}); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%v: want %q in\n%s", tt.format, want, buf.String())
+			}
+		}
+	}
+}
+
+func TestWriteTitleEscaped(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := Write(&buf, 2021, 2021, Options{Format: HTML, Title: "AIRAC <2021>"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "<caption>AIRAC &lt;2021&gt;</caption>"; !strings.Contains(buf.String(), want) {
+		t.Errorf("want %q in\n%s", want, buf.String())
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		first, last int
+		opts        Options
+	}{
+		{2021, 2020, Options{}},
+		{1901, 2020, Options{}},
+		{2020, 2193, Options{}},
+		{2020, 2021, Options{Format: Format(42)}},
+	}
+
+	for _, tt := range testt {
+		if err := Write(&bytes.Buffer{}, tt.first, tt.last, tt.opts); err == nil {
+			t.Errorf("%d-%d %v: want error, got nil", tt.first, tt.last, tt.opts.Format)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []Format{Text, Markdown, HTML, CSV} {
+		got, err := ParseFormat(f.String())
+		if err != nil || got != f {
+			t.Errorf("want %v, got %v, %v", f, got, err)
+		}
+	}
+
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("want error, got nil")
+	}
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package scheduler runs jobs aligned to AIRAC cycles, e.g. three days before
+// each cycle becomes effective. The cycles a job ran for are persisted in a
+// Store, so that after a restart no cycle runs twice and none is skipped.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jwkohnen/airac"
+)
+
+// Rule determines when a job runs for an AIRAC cycle.
+type Rule struct {
+	// Offset is the time relative to the effective date of each cycle. A
+	// negative Offset runs the job before the cycle becomes effective.
+	Offset time.Duration
+}
+
+// AtEffective runs a job when each cycle becomes effective.
+func AtEffective() Rule { return Rule{} }
+
+// BeforeEffective runs a job d before each cycle becomes effective.
+func BeforeEffective(d time.Duration) Rule { return Rule{Offset: -d} }
+
+// AtMilestone runs a job at milestone m of each cycle.
+func AtMilestone(m airac.Milestone) Rule { return Rule{Offset: m.Offset()} }
+
+// At returns the time the rule fires for cycle a.
+func (r Rule) At(a airac.AIRAC) time.Time { return a.Effective().Add(r.Offset) }
+
+// due returns the latest cycle whose rule time is not after t.
+func (r Rule) due(t time.Time) airac.AIRAC { return airac.FromDate(t.Add(-r.Offset)) }
+
+// Job is a task that runs once per AIRAC cycle.
+type Job struct {
+	// Name identifies the job in the Store. It must be unique.
+	Name string
+
+	// Rule determines when the job runs.
+	Rule Rule
+
+	// First is the first cycle to run, if the job never ran before. If zero,
+	// a new job starts with the latest due cycle.
+	First airac.AIRAC
+
+	// Run performs the job for cycle a. If it returns an error, the cycle is
+	// not recorded and is retried later.
+	Run func(ctx context.Context, a airac.AIRAC) error
+}
+
+// Options configures a Scheduler.
+type Options struct {
+	// Store persists the run state. If nil, a MemoryStore is used.
+	Store Store
+
+	// Clock is the time source. If nil, airac.SystemClock is used.
+	Clock airac.Clock
+
+	// Recheck is the longest time to wait before the clock is consulted
+	// again, see airac.NextWakeup. It also is the delay before a failed job
+	// is retried. Zero means airac.DefaultRecheck.
+	Recheck time.Duration
+
+	// OnError is called with errors of jobs and of the Store. If nil, errors
+	// are dropped.
+	OnError func(job string, a airac.AIRAC, err error)
+}
+
+// Scheduler runs registered jobs for each AIRAC cycle.
+type Scheduler struct {
+	opts Options
+
+	mu   sync.Mutex
+	jobs []Job
+}
+
+// ErrDuplicateJob is returned by Register if a job with the same name is
+// already registered.
+var ErrDuplicateJob = errors.New("duplicate job")
+
+// New returns a Scheduler without jobs.
+func New(opts Options) *Scheduler {
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+	if opts.Clock == nil {
+		opts.Clock = airac.SystemClock{}
+	}
+
+	return &Scheduler{opts: opts}
+}
+
+// Register adds a job.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("illegal job %q: name and run function are required", job.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		if j.Name == job.Name {
+			return fmt.Errorf("%w %q", ErrDuplicateJob, job.Name)
+		}
+	}
+	s.jobs = append(s.jobs, job)
+
+	return nil
+}
+
+// Run runs jobs as they become due until ctx is done. It returns ctx.Err().
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		s.RunPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.opts.Clock.After(s.sleep()):
+		}
+	}
+}
+
+// RunPending runs all due jobs once, in registration order. A job that missed
+// several cycles runs for each of them in chronological order. It returns the
+// number of successful runs.
+func (s *Scheduler) RunPending(ctx context.Context) int {
+	s.mu.Lock()
+	jobs := append([]Job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	var n int
+	for _, job := range jobs {
+		n += s.runJob(ctx, job)
+	}
+
+	return n
+}
+
+func (s *Scheduler) runJob(ctx context.Context, job Job) int {
+	next, err := s.next(job)
+	if err != nil {
+		s.onError(job.Name, 0, err)
+		return 0
+	}
+
+	var n int
+	for ; next <= job.Rule.due(s.opts.Clock.Now()); next++ {
+		if ctx.Err() != nil {
+			return n
+		}
+
+		if err := job.Run(ctx, next); err != nil {
+			s.onError(job.Name, next, err)
+			return n
+		}
+
+		if err := s.opts.Store.SetLastRun(job.Name, next); err != nil {
+			s.onError(job.Name, next, err)
+			return n
+		}
+		n++
+	}
+
+	return n
+}
+
+// next returns the next cycle the job has to run for.
+func (s *Scheduler) next(job Job) (airac.AIRAC, error) {
+	last, ok, err := s.opts.Store.LastRun(job.Name)
+	switch {
+	case err != nil:
+		return 0, err
+	case ok:
+		return last + 1, nil
+	case job.First != 0:
+		return job.First, nil
+	default:
+		return job.Rule.due(s.opts.Clock.Now()), nil
+	}
+}
+
+// sleep returns how long to wait for the next job.
+func (s *Scheduler) sleep() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.opts.Clock.Now()
+	boundaries := make([]time.Time, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		boundaries = append(boundaries, job.Rule.At(job.Rule.due(now)+1))
+	}
+
+	return airac.NextWakeup(now, s.opts.Recheck, boundaries...)
+}
+
+func (s *Scheduler) onError(job string, a airac.AIRAC, err error) {
+	if s.opts.OnError != nil {
+		s.opts.OnError(job, a, err)
+	}
+}
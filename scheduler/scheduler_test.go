@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/internal/fakeclock"
+)
+
+const day = 24 * time.Hour
+
+// recorder is a job function that records the cycles it ran for.
+type recorder struct {
+	runs []string
+	fail bool
+}
+
+func (r *recorder) run(_ context.Context, a airac.AIRAC) error {
+	if r.fail {
+		return errors.New("failed")
+	}
+	r.runs = append(r.runs, a.String())
+	return nil
+}
+
+func TestRunPending(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Date(2021, time.January, 24, 12, 0, 0, 0, time.UTC))
+	s := New(Options{Clock: clock})
+
+	var r recorder
+	if err := s.Register(Job{Name: "prepare", Rule: BeforeEffective(3 * day), Run: r.run}); err != nil {
+		t.Fatal(err)
+	}
+
+	s.RunPending(context.Background())
+	clock.Advance(11*time.Hour + 59*time.Minute)
+	s.RunPending(context.Background())
+	clock.Advance(time.Minute)
+	s.RunPending(context.Background())
+	s.RunPending(context.Background())
+
+	if want := []string{"2014", "2101"}; !reflect.DeepEqual(r.runs, want) {
+		t.Errorf("want %v, got %v", want, r.runs)
+	}
+}
+
+func TestRestart(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	clock := fakeclock.New(time.Date(2021, time.January, 28, 0, 0, 0, 0, time.UTC))
+
+	var r recorder
+	job := Job{Name: "publish", Rule: AtMilestone(airac.Publication), Run: r.run}
+
+	s := New(Options{Clock: clock, Store: NewFileStore(path)})
+	if err := s.Register(job); err != nil {
+		t.Fatal(err)
+	}
+	s.RunPending(context.Background())
+
+	// down for two cycles
+	clock.Advance(2 * 28 * day)
+
+	s = New(Options{Clock: clock, Store: NewFileStore(path)})
+	if err := s.Register(job); err != nil {
+		t.Fatal(err)
+	}
+	s.RunPending(context.Background())
+	s.RunPending(context.Background())
+
+	if want := []string{"2102", "2103", "2104"}; !reflect.DeepEqual(r.runs, want) {
+		t.Errorf("want %v, got %v", want, r.runs)
+	}
+}
+
+func TestFirst(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC))
+	s := New(Options{Clock: clock})
+
+	var r recorder
+	err := s.Register(Job{Name: "backfill", Rule: AtEffective(), First: airac.FromStringMust("2014"), Run: r.run})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := s.RunPending(context.Background()); n != 3 {
+		t.Errorf("want 3 runs, got %d", n)
+	}
+	if want := []string{"2014", "2101", "2102"}; !reflect.DeepEqual(r.runs, want) {
+		t.Errorf("want %v, got %v", want, r.runs)
+	}
+}
+
+func TestFailedJobIsRetried(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Date(2021, time.January, 28, 0, 0, 0, 0, time.UTC))
+
+	var errs int
+	s := New(Options{Clock: clock, OnError: func(string, airac.AIRAC, error) { errs++ }})
+
+	r := recorder{fail: true}
+	if err := s.Register(Job{Name: "flaky", Rule: AtEffective(), Run: r.run}); err != nil {
+		t.Fatal(err)
+	}
+
+	s.RunPending(context.Background())
+	r.fail = false
+	s.RunPending(context.Background())
+
+	if errs != 1 {
+		t.Errorf("want 1 error, got %d", errs)
+	}
+	if want := []string{"2101"}; !reflect.DeepEqual(r.runs, want) {
+		t.Errorf("want %v, got %v", want, r.runs)
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	t.Parallel()
+
+	s := New(Options{})
+	job := Job{Name: "job", Run: func(context.Context, airac.AIRAC) error { return nil }}
+
+	if err := s.Register(job); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Register(job); !errors.Is(err, ErrDuplicateJob) {
+		t.Errorf("want %v, got %v", ErrDuplicateJob, err)
+	}
+	if err := s.Register(Job{Name: "nop"}); err == nil {
+		t.Error("job without run function must not register")
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Date(2021, time.January, 27, 12, 0, 0, 0, time.UTC))
+	s := New(Options{Clock: clock})
+
+	ran := make(chan airac.AIRAC, 2)
+	err := s.Register(Job{Name: "job", Rule: AtEffective(), Run: func(_ context.Context, a airac.AIRAC) error {
+		ran <- a
+		return nil
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	if got, want := <-ran, airac.FromStringMust("2014"); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(12 * time.Hour)
+
+	if got, want := <-ran, airac.FromStringMust("2101"); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+}
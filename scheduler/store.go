@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jwkohnen/airac"
+)
+
+// Store persists the latest cycle each job ran for.
+type Store interface {
+	// LastRun returns the latest cycle the job ran for. ok is false if the
+	// job never ran.
+	LastRun(job string) (a airac.AIRAC, ok bool, err error)
+
+	// SetLastRun records that the job ran for cycle a.
+	SetLastRun(job string, a airac.AIRAC) error
+}
+
+// MemoryStore is a Store that does not persist anything.
+type MemoryStore struct {
+	mu   sync.Mutex
+	runs map[string]airac.AIRAC
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{runs: make(map[string]airac.AIRAC)}
+}
+
+// LastRun implements Store.
+func (m *MemoryStore) LastRun(job string) (airac.AIRAC, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, ok := m.runs[job]
+	return a, ok, nil
+}
+
+// SetLastRun implements Store.
+func (m *MemoryStore) SetLastRun(job string, a airac.AIRAC) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.runs[job] = a
+	return nil
+}
+
+// FileStore is a Store backed by a JSON file. The file is replaced atomically
+// on each update.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a FileStore that keeps its state in the file at path.
+// The file is created on the first update.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// fileRun is the persisted form of a job's last run. The effective date is
+// authoritative, the identifier is informational only.
+type fileRun struct {
+	Cycle     string `json:"cycle"`
+	Effective string `json:"effective"`
+}
+
+const dateFormat = "2006-01-02"
+
+// LastRun implements Store.
+func (f *FileStore) LastRun(job string) (airac.AIRAC, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	runs, err := f.read()
+	if err != nil {
+		return 0, false, err
+	}
+
+	run, ok := runs[job]
+	if !ok {
+		return 0, false, nil
+	}
+
+	effective, err := time.Parse(dateFormat, run.Effective)
+	if err != nil {
+		return 0, false, fmt.Errorf("scheduler store %s: job %q: %w", f.path, job, err)
+	}
+
+	return airac.FromDate(effective), true, nil
+}
+
+// SetLastRun implements Store.
+func (f *FileStore) SetLastRun(job string, a airac.AIRAC) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	runs, err := f.read()
+	if err != nil {
+		return err
+	}
+	runs[job] = fileRun{Cycle: a.String(), Effective: a.Effective().Format(dateFormat)}
+
+	return f.write(runs)
+}
+
+func (f *FileStore) read() (map[string]fileRun, error) {
+	runs := make(map[string]fileRun)
+
+	b, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return runs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &runs); err != nil {
+		return nil, fmt.Errorf("scheduler store %s: %w", f.path, err)
+	}
+
+	return runs, nil
+}
+
+func (f *FileStore) write(runs map[string]fileRun) (err error) {
+	b, err := json.MarshalIndent(runs, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// static assert
+var (
+	_ Store = (*MemoryStore)(nil)
+	_ Store = (*FileStore)(nil)
+)
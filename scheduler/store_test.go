@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwkohnen/airac"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := NewFileStore(path)
+
+	if _, ok, err := store.LastRun("job"); ok || err != nil {
+		t.Fatalf("empty store: want not ok and no error, got %t, %v", ok, err)
+	}
+
+	want := airac.FromStringMust("2014")
+	if err := store.SetLastRun("job", want); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetLastRun("other", want+1); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok, err := NewFileStore(path).LastRun("job")
+	if !ok || err != nil {
+		t.Fatalf("want ok and no error, got %t, %v", ok, err)
+	}
+	if got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestFileStoreCorrupt(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := NewFileStore(path).LastRun("job"); err == nil {
+		t.Error("corrupt state file must yield an error")
+	}
+}
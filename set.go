@@ -0,0 +1,280 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Set is a set of AIRAC cycles, e.g. the cycles a collection of datasets
+// covers. It is stored as the sorted list of its maximal runs of consecutive
+// cycles, so that years of uninterrupted coverage take a single Range.
+//
+// The zero value is an empty set ready to use. Add, AddRange, Remove and
+// RemoveRange modify the set in place; use Clone before modifying a copy that
+// must not affect the original. All other methods leave the set unchanged.
+type Set struct {
+	runs []Range
+}
+
+// NewSet returns the set of the given cycles.
+func NewSet(cycles ...AIRAC) Set {
+	var s Set
+	for _, a := range cycles {
+		s.Add(a)
+	}
+	return s
+}
+
+// SetFromRanges returns the set of all cycles within the given ranges. The
+// ranges may overlap and be in any order; empty ranges are ignored.
+func SetFromRanges(ranges ...Range) Set {
+	var s Set
+	for _, r := range ranges {
+		s.AddRange(r)
+	}
+	return s
+}
+
+// ParseSet parses the text form of a set as returned by String, i.e. a comma
+// separated list of ranges as accepted by ParseRange, e.g.
+// "2101-2106,2109,2111..2113". Blanks around the elements are ignored and the
+// empty string is the empty set.
+func ParseSet(s string) (Set, error) {
+	var set Set
+	if strings.TrimSpace(s) == "" {
+		return set, nil
+	}
+
+	for _, elem := range strings.Split(s, ",") {
+		r, err := ParseRange(strings.TrimSpace(elem))
+		if err != nil {
+			return Set{}, fmt.Errorf("illegal AIRAC set %q: %w", s, err)
+		}
+		set.AddRange(r)
+	}
+
+	return set, nil
+}
+
+// Add adds cycle a to the set.
+func (s *Set) Add(a AIRAC) { s.AddRange(Range{First: a, Last: a}) }
+
+// AddRange adds all cycles of r to the set.
+func (s *Set) AddRange(r Range) {
+	if r.Empty() {
+		return
+	}
+
+	// Merge r with all runs it overlaps or touches.
+	i := sort.Search(len(s.runs), func(i int) bool { return int(s.runs[i].Last)+1 >= int(r.First) })
+	j := i
+	for ; j < len(s.runs) && int(s.runs[j].First) <= int(r.Last)+1; j++ {
+		if s.runs[j].First < r.First {
+			r.First = s.runs[j].First
+		}
+		if s.runs[j].Last > r.Last {
+			r.Last = s.runs[j].Last
+		}
+	}
+
+	s.runs = slices.Replace(s.runs, i, j, r)
+}
+
+// Remove removes cycle a from the set.
+func (s *Set) Remove(a AIRAC) { s.RemoveRange(Range{First: a, Last: a}) }
+
+// RemoveRange removes all cycles of r from the set.
+func (s *Set) RemoveRange(r Range) {
+	if r.Empty() {
+		return
+	}
+
+	i := sort.Search(len(s.runs), func(i int) bool { return s.runs[i].Last >= r.First })
+	j := i
+	for j < len(s.runs) && s.runs[j].First <= r.Last {
+		j++
+	}
+	if i == j {
+		return
+	}
+
+	// Keep the parts of the first and last overlapped run that stick out of r.
+	var rest []Range
+	if first := s.runs[i].First; first < r.First {
+		rest = append(rest, Range{First: first, Last: r.First - 1})
+	}
+	if last := s.runs[j-1].Last; last > r.Last {
+		rest = append(rest, Range{First: r.Last + 1, Last: last})
+	}
+
+	s.runs = slices.Replace(s.runs, i, j, rest...)
+}
+
+// Clone returns a copy of the set that does not share memory with s.
+func (s Set) Clone() Set { return Set{runs: slices.Clone(s.runs)} }
+
+// Empty reports whether the set contains no cycles.
+func (s Set) Empty() bool { return len(s.runs) == 0 }
+
+// Len returns the number of cycles in the set.
+func (s Set) Len() int {
+	n := 0
+	for _, r := range s.runs {
+		n += r.Len()
+	}
+	return n
+}
+
+// Contains reports whether cycle a is in the set.
+func (s Set) Contains(a AIRAC) bool {
+	i := sort.Search(len(s.runs), func(i int) bool { return s.runs[i].Last >= a })
+	return i < len(s.runs) && s.runs[i].Contains(a)
+}
+
+// Equal reports whether s and t contain the same cycles.
+func (s Set) Equal(t Set) bool { return slices.Equal(s.runs, t.runs) }
+
+// Ranges returns the maximal runs of consecutive cycles of the set in
+// chronological order. No two ranges overlap or touch.
+func (s Set) Ranges() []Range { return slices.Clone(s.runs) }
+
+// Cycles returns all cycles of the set in chronological order.
+func (s Set) Cycles() []AIRAC {
+	cycles := make([]AIRAC, 0, s.Len())
+	for a := range s.All() {
+		cycles = append(cycles, a)
+	}
+	return cycles
+}
+
+// All returns an iterator over the cycles of the set in chronological order.
+func (s Set) All() iter.Seq[AIRAC] {
+	return func(yield func(AIRAC) bool) {
+		for _, r := range s.runs {
+			for a := r.First; ; a++ {
+				if !yield(a) {
+					return
+				}
+				if a == r.Last {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Union returns the set of cycles that are in s or t.
+func (s Set) Union(t Set) Set {
+	runs := make([]Range, 0, len(s.runs)+len(t.runs))
+
+	i, j := 0, 0
+	for i < len(s.runs) || j < len(t.runs) {
+		var r Range
+		if j == len(t.runs) || (i < len(s.runs) && s.runs[i].First <= t.runs[j].First) {
+			r, i = s.runs[i], i+1
+		} else {
+			r, j = t.runs[j], j+1
+		}
+
+		if n := len(runs); n > 0 && int(r.First) <= int(runs[n-1].Last)+1 {
+			if r.Last > runs[n-1].Last {
+				runs[n-1].Last = r.Last
+			}
+			continue
+		}
+		runs = append(runs, r)
+	}
+
+	return Set{runs: runs}
+}
+
+// Intersect returns the set of cycles that are in both s and t.
+func (s Set) Intersect(t Set) Set {
+	var runs []Range
+
+	i, j := 0, 0
+	for i < len(s.runs) && j < len(t.runs) {
+		r := Range{First: max(s.runs[i].First, t.runs[j].First), Last: min(s.runs[i].Last, t.runs[j].Last)}
+		if !r.Empty() {
+			runs = append(runs, r)
+		}
+
+		if s.runs[i].Last < t.runs[j].Last {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return Set{runs: runs}
+}
+
+// Difference returns the set of cycles that are in s but not in t.
+func (s Set) Difference(t Set) Set {
+	var runs []Range
+
+	j := 0
+	for _, r := range s.runs {
+		for j < len(t.runs) && t.runs[j].Last < r.First {
+			j++
+		}
+
+		// Cut the runs of t that overlap r out of it. The last of them may
+		// overlap the next run of s, too, so j stays put.
+		first := int(r.First)
+		for k := j; k < len(t.runs) && t.runs[k].First <= r.Last; k++ {
+			if int(t.runs[k].First) > first {
+				runs = append(runs, Range{First: AIRAC(first), Last: t.runs[k].First - 1})
+			}
+			first = int(t.runs[k].Last) + 1
+		}
+		if first <= int(r.Last) {
+			runs = append(runs, Range{First: AIRAC(first), Last: r.Last})
+		}
+	}
+
+	return Set{runs: runs}
+}
+
+// String returns the compact text form of the set, i.e. its ranges separated
+// by commas, e.g. "2101-2106,2109,2111-2113". The empty set returns an empty
+// string.
+func (s Set) String() string {
+	elems := make([]string, len(s.runs))
+	for i, r := range s.runs {
+		elems[i] = r.String()
+	}
+	return strings.Join(elems, ",")
+}
+
+// MarshalText implements encoding.TextMarshaler using the text form of String.
+func (s Set) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseSet.
+func (s *Set) UnmarshalText(text []byte) error {
+	set, err := ParseSet(string(text))
+	if err != nil {
+		return err
+	}
+	*s = set
+	return nil
+}
@@ -0,0 +1,235 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func set(t *testing.T, s string) Set {
+	t.Helper()
+	set, err := ParseSet(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return set
+}
+
+func ident(t *testing.T, s string) AIRAC {
+	t.Helper()
+	a, err := FromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestParseSet(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		in   string
+		want string
+		len  int
+	}{
+		{"", "", 0},
+		{"2101", "2101", 1},
+		{"2101-2106,2109,2111-2113", "2101-2106,2109,2111-2113", 10},
+		{"2111..2113, 2109 ,2101-2106", "2101-2106,2109,2111-2113", 10},
+		{"2101-2103,2104,2105-2106", "2101-2106", 6},
+		{"2101-2106,2103-2109", "2101-2109", 9},
+		{"2013,2014,2101", "2013-2101", 3},
+	}
+
+	for _, tt := range testt {
+		s := set(t, tt.in)
+		if s.String() != tt.want || s.Len() != tt.len {
+			t.Errorf("%q: want %q (%d cycles), got %q (%d)", tt.in, tt.want, tt.len, s, s.Len())
+		}
+	}
+
+	for _, in := range []string{",", "2101,", "2101-2106,x", "2106-2101", "2115"} {
+		if _, err := ParseSet(in); err == nil {
+			t.Errorf("%q: want error, got nil", in)
+		}
+	}
+}
+
+func TestSetAddRemove(t *testing.T) {
+	t.Parallel()
+
+	var s Set
+	for _, a := range []string{"2105", "2101", "2103", "2102", "2104"} {
+		s.Add(ident(t, a))
+	}
+	if got := s.String(); got != "2101-2105" {
+		t.Errorf("want 2101-2105, got %q", got)
+	}
+
+	s.Remove(ident(t, "2103"))
+	s.Remove(ident(t, "2109"))
+	if got := s.String(); got != "2101-2102,2104-2105" {
+		t.Errorf("want 2101-2102,2104-2105, got %q", got)
+	}
+
+	s.AddRange(Range{First: ident(t, "2011"), Last: ident(t, "2112")})
+	s.RemoveRange(Range{First: ident(t, "2013"), Last: ident(t, "2101")})
+	s.RemoveRange(Range{First: ident(t, "2110"), Last: ident(t, "2201")})
+	if got := s.String(); got != "2011-2012,2102-2109" {
+		t.Errorf("want 2011-2012,2102-2109, got %q", got)
+	}
+
+	if !s.Contains(ident(t, "2012")) || s.Contains(ident(t, "2101")) || s.Contains(ident(t, "2110")) {
+		t.Errorf("%s: wrong Contains", s)
+	}
+
+	c := s.Clone()
+	c.Add(ident(t, "2101"))
+	if s.Contains(ident(t, "2101")) || !c.Contains(ident(t, "2101")) {
+		t.Errorf("want clone to be independent")
+	}
+}
+
+func TestSetBounds(t *testing.T) {
+	t.Parallel()
+
+	s := NewSet(0, 1, 65534, 65535)
+	if want := []Range{{0, 1}, {65534, 65535}}; !s.Equal(SetFromRanges(want...)) || len(s.Ranges()) != 2 {
+		t.Errorf("want %v, got %v", want, s.Ranges())
+	}
+	if got := s.Cycles(); len(got) != 4 || got[3] != 65535 {
+		t.Errorf("want 4 cycles up to 65535, got %v", got)
+	}
+
+	s.Remove(65535)
+	s.Remove(0)
+	if got := s.Ranges(); len(got) != 2 || got[0] != (Range{1, 1}) || got[1] != (Range{65534, 65534}) {
+		t.Errorf("want [{1 1} {65534 65534}], got %v", got)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		a, b                        string
+		union, intersect, diff, rev string
+	}{
+		{"", "", "", "", "", ""},
+		{"2101-2106", "", "2101-2106", "", "2101-2106", ""},
+		{"2101-2106", "2104-2109", "2101-2109", "2104-2106", "2101-2103", "2107-2109"},
+		{"2101-2103", "2104-2106", "2101-2106", "", "2101-2103", "2104-2106"},
+		{"2101-2113", "2103,2105-2106,2110", "2101-2113", "2103,2105-2106,2110", "2101-2102,2104,2107-2109,2111-2113", ""},
+		{"2101-2103,2107-2109", "2102-2108", "2101-2109", "2102-2103,2107-2108", "2101,2109", "2104-2106"},
+	}
+
+	for _, tt := range testt {
+		a, b := set(t, tt.a), set(t, tt.b)
+		if got := a.Union(b).String(); got != tt.union {
+			t.Errorf("%q ∪ %q: want %q, got %q", tt.a, tt.b, tt.union, got)
+		}
+		if got := a.Intersect(b).String(); got != tt.intersect {
+			t.Errorf("%q ∩ %q: want %q, got %q", tt.a, tt.b, tt.intersect, got)
+		}
+		if got := a.Difference(b).String(); got != tt.diff {
+			t.Errorf("%q \\ %q: want %q, got %q", tt.a, tt.b, tt.diff, got)
+		}
+		if got := b.Difference(a).String(); got != tt.rev {
+			t.Errorf("%q \\ %q: want %q, got %q", tt.b, tt.a, tt.rev, got)
+		}
+		if a.String() != set(t, tt.a).String() {
+			t.Errorf("%q: want operations to leave the set unchanged, got %q", tt.a, a)
+		}
+	}
+}
+
+// TestSetRandom compares random operations with a map of cycles.
+func TestSetRandom(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(1)) // nolint:gosec
+	random := func() (Set, map[AIRAC]bool) {
+		var s Set
+		m := make(map[AIRAC]bool)
+		for i := 0; i < 20; i++ {
+			r := Range{First: AIRAC(rnd.Intn(100)), Last: AIRAC(rnd.Intn(100))}
+			add := rnd.Intn(3) != 0
+			if add {
+				s.AddRange(r)
+			} else {
+				s.RemoveRange(r)
+			}
+			for a := r.First; r.Contains(a); a++ {
+				m[a] = add
+			}
+		}
+		return s, m
+	}
+
+	check := func(name string, s Set, want func(a AIRAC) bool) {
+		n := 0
+		for a := AIRAC(0); a < 100; a++ {
+			if s.Contains(a) != want(a) {
+				t.Fatalf("%s %s: want Contains(%d) %v", name, s, a, want(a))
+			}
+			if want(a) {
+				n++
+			}
+		}
+		if s.Len() != n || len(s.Cycles()) != n {
+			t.Fatalf("%s %s: want %d cycles, got %d", name, s, n, s.Len())
+		}
+		runs := s.Ranges()
+		for i := 1; i < len(runs); i++ {
+			if int(runs[i].First) <= int(runs[i-1].Last)+1 {
+				t.Fatalf("%s %s: want disjoint, non-adjacent runs", name, s)
+			}
+		}
+	}
+
+	for i := 0; i < 500; i++ {
+		a, ma := random()
+		b, mb := random()
+		check("a", a, func(c AIRAC) bool { return ma[c] })
+		check("union", a.Union(b), func(c AIRAC) bool { return ma[c] || mb[c] })
+		check("intersect", a.Intersect(b), func(c AIRAC) bool { return ma[c] && mb[c] })
+		check("difference", a.Difference(b), func(c AIRAC) bool { return ma[c] && !mb[c] })
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	t.Parallel()
+
+	in := struct{ Coverage Set }{set(t, "2101-2106,2109")}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Coverage":"2101-2106,2109"}`; string(b) != want {
+		t.Errorf("want %s, got %s", want, b)
+	}
+
+	var out struct{ Coverage Set }
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.Coverage.Equal(in.Coverage) {
+		t.Errorf("want %s, got %s", in.Coverage, out.Coverage)
+	}
+}
@@ -0,0 +1,398 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package store resolves datasets kept in one directory per AIRAC cycle, e.g.
+// /data/navdb/2101/ and /data/navdb/2102/, and maintains a "current" pointer
+// next to them that always names the directory of the effective cycle.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jwkohnen/airac"
+)
+
+const defaultPointer = "current"
+
+var (
+	// ErrNotFound is returned if there is no directory for a cycle.
+	ErrNotFound = errors.New("no directory for cycle")
+
+	// ErrNoPointer is returned by Current if the current pointer does not
+	// exist.
+	ErrNoPointer = errors.New("no current pointer")
+
+	// ErrReadOnly is returned by SetCurrent and Update of a Store that was
+	// not opened on a directory of the real filesystem.
+	ErrReadOnly = errors.New("store is read-only")
+)
+
+// Options configures a Store.
+type Options struct {
+	// Precise switches cycles at 00:01 UTC instead of 00:00 UTC, see
+	// airac.FromDatePrecise.
+	Precise bool
+
+	// Pointer is the name of the current pointer. Empty means "current".
+	Pointer string
+
+	// Marker makes SetCurrent write the pointer as a regular file holding
+	// the name of the directory instead of a symbolic link to it. Current
+	// reads either kind.
+	Marker bool
+
+	// Clock is the time source of Run. If nil, airac.SystemClock is used.
+	Clock airac.Clock
+
+	// Recheck is the longest time Run waits before the clock is consulted
+	// again, see airac.NextWakeup. It also is the delay before a failed
+	// update is retried. Zero means airac.DefaultRecheck.
+	Recheck time.Duration
+
+	// OnSwitch is called by Run after the pointer has been moved to the
+	// directory of a new cycle.
+	OnSwitch func(Entry)
+
+	// OnError is called by Run with errors of updates. If nil, errors are
+	// dropped.
+	OnError func(error)
+}
+
+// Entry is the directory of a cycle.
+type Entry struct {
+	Cycle airac.AIRAC
+
+	// Name is the name of the directory within the store, e.g. "2101".
+	Name string
+}
+
+// Store is a directory with one subdirectory per cycle, named by the cycle
+// identifier as accepted by airac.FromString. Other entries are ignored.
+type Store struct {
+	fsys fs.FS
+	dir  string
+	opts Options
+}
+
+// New returns a read-only Store on the root of fsys.
+func New(fsys fs.FS, opts Options) *Store {
+	return newStore(fsys, "", opts)
+}
+
+// Open returns a Store on dir of the real filesystem, which can update the
+// current pointer.
+func Open(dir string, opts Options) *Store {
+	return newStore(os.DirFS(dir), dir, opts)
+}
+
+func newStore(fsys fs.FS, dir string, opts Options) *Store {
+	if opts.Pointer == "" {
+		opts.Pointer = defaultPointer
+	}
+	if opts.Clock == nil {
+		opts.Clock = airac.SystemClock{}
+	}
+	if opts.Recheck <= 0 {
+		opts.Recheck = airac.DefaultRecheck
+	}
+
+	return &Store{fsys: fsys, dir: dir, opts: opts}
+}
+
+// Cycles returns the directories of all cycles in chronological order.
+func (s *Store) Cycles() ([]Entry, error) {
+	dirents, err := fs.ReadDir(s.fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, d := range dirents {
+		if d.Name() == s.opts.Pointer {
+			continue
+		}
+
+		a, err := airac.FromString(d.Name())
+		if err != nil {
+			continue
+		}
+
+		if !d.IsDir() {
+			// A symbolic link to a directory elsewhere counts as well.
+			if d.Type()&fs.ModeSymlink == 0 {
+				continue
+			}
+			if fi, err := fs.Stat(s.fsys, d.Name()); err != nil || !fi.IsDir() {
+				continue
+			}
+		}
+
+		entries = append(entries, Entry{Cycle: a, Name: d.Name()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Cycle < entries[j].Cycle })
+
+	return entries, nil
+}
+
+// Lookup returns the directory of cycle a. It returns an error wrapping
+// ErrNotFound if there is none.
+func (s *Store) Lookup(a airac.AIRAC) (Entry, error) {
+	entries, err := s.Cycles()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	for _, e := range entries {
+		if e.Cycle == a {
+			return e, nil
+		}
+	}
+
+	return Entry{}, fmt.Errorf("%w %s", ErrNotFound, a)
+}
+
+// Effective returns the directory of the cycle effective at t. It returns an
+// error wrapping ErrNotFound if there is none; an older cycle is never
+// substituted.
+func (s *Store) Effective(t time.Time) (Entry, error) {
+	return s.Lookup(s.cycle(t))
+}
+
+// Pending returns the directories of the cycles after the one effective at t
+// in chronological order, i.e. datasets distributed ahead of their effective
+// date.
+func (s *Store) Pending(t time.Time) ([]Entry, error) {
+	entries, err := s.Cycles()
+	if err != nil {
+		return nil, err
+	}
+
+	cur := s.cycle(t)
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].Cycle > cur })
+
+	return entries[i:], nil
+}
+
+// Current returns the directory the current pointer names. It returns an
+// error wrapping ErrNoPointer if there is no pointer, and one wrapping
+// ErrNotFound if the pointer names no cycle directory.
+func (s *Store) Current() (Entry, error) {
+	fi, err := s.lstat(s.opts.Pointer)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Entry{}, fmt.Errorf("%w %q", ErrNoPointer, s.opts.Pointer)
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+
+	var name string
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		name, err = s.readLink(s.opts.Pointer)
+		name = path.Base(filepath.ToSlash(name))
+	} else {
+		var b []byte
+		b, err = fs.ReadFile(s.fsys, s.opts.Pointer)
+		name = strings.TrimSpace(string(b))
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+
+	a, err := airac.FromString(name)
+	if err != nil {
+		return Entry{}, fmt.Errorf("pointer %q: %w", s.opts.Pointer, err)
+	}
+
+	return s.Lookup(a)
+}
+
+// SetCurrent points the current pointer to the directory of cycle a. The
+// pointer is replaced atomically by renaming a new one over it, so readers
+// see either the old or the new directory. It returns an error wrapping
+// ErrNotFound if there is no directory for a.
+func (s *Store) SetCurrent(a airac.AIRAC) error {
+	if s.dir == "" {
+		return ErrReadOnly
+	}
+
+	e, err := s.Lookup(a)
+	if err != nil {
+		return err
+	}
+
+	if s.opts.Marker {
+		return s.writeMarker(e.Name)
+	}
+	return s.writeSymlink(e.Name)
+}
+
+// Update points the current pointer to the directory of the cycle effective
+// at t, unless it already does. It reports whether the pointer moved. If there
+// is no directory for the cycle, the pointer is left alone and the error wraps
+// ErrNotFound.
+func (s *Store) Update(t time.Time) (e Entry, moved bool, err error) {
+	e, err = s.Effective(t)
+	if err != nil {
+		return Entry{}, false, err
+	}
+
+	if cur, err := s.Current(); err == nil && cur == e {
+		return e, false, nil
+	}
+
+	if err := s.SetCurrent(e.Cycle); err != nil {
+		return Entry{}, false, err
+	}
+
+	return e, true, nil
+}
+
+// Run updates the current pointer now and at each cycle switch until ctx is
+// done. Failed updates are passed to OnError and retried after Recheck. It
+// returns ctx.Err().
+func (s *Store) Run(ctx context.Context) error {
+	opts := airac.TickerOptions{Clock: s.opts.Clock, Recheck: s.opts.Recheck}
+	if s.opts.Precise {
+		opts.Offset = airac.PreciseOffset
+	}
+
+	ticker := airac.NewCycleTicker(ctx, opts)
+	defer ticker.Stop()
+
+	for {
+		var retry <-chan time.Time
+
+		e, moved, err := s.Update(s.opts.Clock.Now())
+		switch {
+		case err != nil:
+			if s.opts.OnError != nil {
+				s.opts.OnError(err)
+			}
+			retry = s.opts.Clock.After(s.opts.Recheck)
+		case moved && s.opts.OnSwitch != nil:
+			s.opts.OnSwitch(e)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-ticker.C:
+			if !ok {
+				return ctx.Err()
+			}
+		case <-retry:
+		}
+	}
+}
+
+// linkFS is implemented by file systems that report symbolic links instead of
+// following them, e.g. os.DirFS and fstest.MapFS as of Go 1.25.
+type linkFS interface {
+	Lstat(name string) (fs.FileInfo, error)
+	ReadLink(name string) (string, error)
+}
+
+func (s *Store) lstat(name string) (fs.FileInfo, error) {
+	if fsys, ok := s.fsys.(linkFS); ok {
+		return fsys.Lstat(name)
+	}
+	if s.dir != "" {
+		return os.Lstat(filepath.Join(s.dir, name))
+	}
+	return fs.Stat(s.fsys, name)
+}
+
+func (s *Store) readLink(name string) (string, error) {
+	if fsys, ok := s.fsys.(linkFS); ok {
+		return fsys.ReadLink(name)
+	}
+	if s.dir != "" {
+		return os.Readlink(filepath.Join(s.dir, name))
+	}
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: errors.ErrUnsupported}
+}
+
+func (s *Store) cycle(t time.Time) airac.AIRAC {
+	if s.opts.Precise {
+		return airac.FromDatePrecise(t)
+	}
+	return airac.FromDate(t)
+}
+
+func (s *Store) writeSymlink(name string) error {
+	tmp, err := s.tempName()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Symlink(name, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, s.opts.Pointer)); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store) writeMarker(name string) (err error) {
+	tmp, err := os.CreateTemp(s.dir, "."+s.opts.Pointer+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := tmp.WriteString(name + "\n"); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, s.opts.Pointer))
+}
+
+// tempName returns an unused name for a new pointer next to the current one.
+func (s *Store) tempName() (string, error) {
+	f, err := os.CreateTemp(s.dir, "."+s.opts.Pointer+".*")
+	if err != nil {
+		return "", err
+	}
+	name := f.Name()
+	_ = f.Close()
+
+	return name, os.Remove(name)
+}
@@ -0,0 +1,308 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package store
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jwkohnen/airac"
+	"github.com/jwkohnen/airac/internal/fakeclock"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func names(entries []Entry) []string {
+	s := make([]string, len(entries))
+	for i, e := range entries {
+		s[i] = e.Name
+	}
+	return s
+}
+
+// nolint:gochecknoglobals
+var mapFS = fstest.MapFS{
+	"2014/nav.dat":  {Data: []byte("2014")},
+	"2101/nav.dat":  {Data: []byte("2101")},
+	"2103/nav.dat":  {Data: []byte("2103")},
+	"2102/nav.dat":  {Data: []byte("2102")},
+	"2104":          {Data: []byte("not a directory")},
+	"2199/nav.dat":  {Data: []byte("not a cycle")},
+	"tmp/nav.dat":   {Data: []byte("not a cycle")},
+	"current":       {Data: []byte("2101"), Mode: fs.ModeSymlink},
+	"marker":        {Data: []byte("2102\n")},
+	"stale":         {Data: []byte("2013\n")},
+	"linked":        {Data: []byte("/data/navdb/2014"), Mode: fs.ModeSymlink},
+	"2105":          {Data: []byte("2103"), Mode: fs.ModeSymlink},
+	"README.md":     {Data: []byte("# navdb")},
+	"2013.tar.gz":   {Data: []byte{}},
+	"2012/.keep":    {Data: []byte{}},
+	"2012/data.csv": {Data: []byte{}},
+}
+
+func TestCycles(t *testing.T) {
+	t.Parallel()
+
+	s := New(mapFS, Options{})
+
+	entries, err := s.Cycles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := []string{"2012", "2014", "2101", "2102", "2103", "2105"}, names(entries); !slices.Equal(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	if entries[0].Cycle.String() != "2012" {
+		t.Errorf("want cycle 2012, got %s", entries[0].Cycle)
+	}
+}
+
+func TestEffective(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		at      string
+		precise bool
+		want    string
+		pending []string
+	}{
+		{"2021-01-28 00:00", false, "2101", []string{"2102", "2103", "2105"}},
+		{"2021-01-28 00:00", true, "2014", []string{"2101", "2102", "2103", "2105"}},
+		{"2021-03-30 12:00", false, "2103", []string{"2105"}},
+		{"2021-04-23 12:00", false, "", []string{"2105"}},
+		{"2021-06-20 12:00", false, "", nil},
+	}
+
+	for _, tt := range testt {
+		s := New(mapFS, Options{Precise: tt.precise})
+
+		e, err := s.Effective(date(tt.at))
+		switch {
+		case tt.want == "" && !errors.Is(err, ErrNotFound):
+			t.Errorf("%s: want ErrNotFound, got %v", tt.at, err)
+		case tt.want != "" && (err != nil || e.Name != tt.want):
+			t.Errorf("%s: want %s, got %s, %v", tt.at, tt.want, e.Name, err)
+		}
+
+		pending, err := s.Pending(date(tt.at))
+		if err != nil || !slices.Equal(names(pending), tt.pending) {
+			t.Errorf("%s: want pending %v, got %v, %v", tt.at, tt.pending, names(pending), err)
+		}
+	}
+}
+
+func TestCurrentMapFS(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		pointer string
+		want    string
+		err     error
+	}{
+		{"current", "2101", nil},
+		{"marker", "2102", nil},
+		{"linked", "2014", nil},
+		{"missing", "", ErrNoPointer},
+		{"stale", "", ErrNotFound},
+	}
+
+	for _, tt := range testt {
+		e, err := New(mapFS, Options{Pointer: tt.pointer}).Current()
+		if !errors.Is(err, tt.err) || e.Name != tt.want {
+			t.Errorf("%s: want %q, %v, got %q, %v", tt.pointer, tt.want, tt.err, e.Name, err)
+		}
+	}
+
+	if err := New(mapFS, Options{}).SetCurrent(airac.FromStringMust("2102")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("want ErrReadOnly, got %v", err)
+	}
+}
+
+func tempStore(t *testing.T, cycles ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, c := range cycles {
+		if err := os.Mkdir(filepath.Join(dir, c), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSetCurrent(t *testing.T) {
+	t.Parallel()
+
+	for _, marker := range []bool{false, true} {
+		dir := tempStore(t, "2101", "2102")
+		s := Open(dir, Options{Marker: marker})
+
+		if _, err := s.Current(); !errors.Is(err, ErrNoPointer) {
+			t.Errorf("marker %v: want ErrNoPointer, got %v", marker, err)
+		}
+
+		for _, c := range []string{"2101", "2102"} {
+			if err := s.SetCurrent(airac.FromStringMust(c)); err != nil {
+				t.Fatal(err)
+			}
+			if e, err := s.Current(); err != nil || e.Name != c {
+				t.Errorf("marker %v: want %s, got %s, %v", marker, c, e.Name, err)
+			}
+		}
+
+		fi, err := os.Lstat(filepath.Join(dir, "current"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if symlink := fi.Mode()&fs.ModeSymlink != 0; symlink == marker {
+			t.Errorf("marker %v: want symlink %v, got mode %s", marker, !marker, fi.Mode())
+		}
+
+		if err := s.SetCurrent(airac.FromStringMust("2103")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("marker %v: want ErrNotFound, got %v", marker, err)
+		}
+
+		dirents, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(dirents) != 3 {
+			t.Errorf("marker %v: want no temporary files, got %d entries", marker, len(dirents))
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+
+	s := Open(tempStore(t, "2101", "2102"), Options{})
+
+	testt := []struct {
+		at    string
+		want  string
+		moved bool
+		err   error
+	}{
+		{"2021-02-03 12:00", "2101", true, nil},
+		{"2021-02-04 12:00", "2101", false, nil},
+		{"2021-02-25 00:00", "2102", true, nil},
+		{"2021-03-25 00:00", "", false, ErrNotFound},
+	}
+
+	for _, tt := range testt {
+		e, moved, err := s.Update(date(tt.at))
+		if !errors.Is(err, tt.err) || e.Name != tt.want || moved != tt.moved {
+			t.Errorf("%s: want %q, %v, %v, got %q, %v, %v", tt.at, tt.want, tt.moved, tt.err, e.Name, moved, err)
+		}
+	}
+
+	if e, err := s.Current(); err != nil || e.Name != "2102" {
+		t.Errorf("want pointer left at 2102, got %s, %v", e.Name, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(date("2021-02-24 23:00"))
+	switches := make(chan Entry)
+	errs := make(chan error, 10)
+	s := Open(tempStore(t, "2101", "2102"), Options{
+		Clock:    clock,
+		Recheck:  24 * time.Hour,
+		OnSwitch: func(e Entry) { switches <- e },
+		OnError:  func(err error) { errs <- err },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	if e := <-switches; e.Name != "2101" {
+		t.Errorf("want initial switch to 2101, got %s", e.Name)
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	if e := <-switches; e.Name != "2102" {
+		t.Errorf("want switch to 2102 at the cycle boundary, got %s", e.Name)
+	}
+	if now := clock.Now(); !now.Equal(date("2021-02-25 00:00")) {
+		t.Errorf("want switch at 2021-02-25 00:00, got %s", now)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+	if len(errs) > 0 {
+		t.Errorf("want no errors, got %v", <-errs)
+	}
+}
+
+func TestRunRetry(t *testing.T) {
+	t.Parallel()
+
+	dir := tempStore(t, "2101")
+	clock := fakeclock.New(date("2021-02-25 00:00"))
+	switches := make(chan Entry)
+	errs := make(chan error)
+	s := Open(dir, Options{
+		Precise:  true,
+		Clock:    clock,
+		Recheck:  time.Hour,
+		OnSwitch: func(e Entry) { switches <- e },
+		OnError:  func(err error) { errs <- err },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Run(ctx) }()
+
+	if e := <-switches; e.Name != "2101" {
+		t.Errorf("want initial switch to 2101 before 00:01, got %s", e.Name)
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	if err := <-errs; !errors.Is(err, ErrNotFound) {
+		t.Errorf("want ErrNotFound for 2102 at 00:01, got %v", err)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "2102"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.BlockUntil(2)
+	clock.Advance(time.Hour)
+	if e := <-switches; e.Name != "2102" {
+		t.Errorf("want switch to 2102 on retry, got %s", e.Name)
+	}
+}
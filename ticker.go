@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultRecheck is the recheck interval of NextWakeup if none is given.
+const DefaultRecheck = time.Minute
+
+// TickerOptions configures NewCycleTicker and WaitUntilEffective.
+type TickerOptions struct {
+	// Clock is the time source. If nil, SystemClock is used.
+	Clock Clock
+
+	// Offset shifts the boundary of each cycle relative to its effective
+	// date. A negative Offset fires early, e.g. -7*24*time.Hour fires 7 days
+	// before the next cycle becomes effective.
+	Offset time.Duration
+
+	// Recheck is the longest time to wait before the clock is consulted
+	// again, see NextWakeup. Zero means DefaultRecheck.
+	Recheck time.Duration
+}
+
+// CycleTicker delivers each AIRAC cycle on its boundary.
+type CycleTicker struct {
+	// C delivers the new AIRAC cycle on each boundary. If the ticker missed
+	// more than one boundary, e.g. because the system was suspended, only
+	// the latest cycle is delivered. C is closed after the ticker stopped.
+	C <-chan AIRAC
+
+	// Current is the cycle that was current when the ticker was created. It
+	// is not delivered on C; the first cycle delivered is a later one.
+	Current AIRAC
+
+	cancel context.CancelFunc
+}
+
+// NewCycleTicker returns a CycleTicker that runs until ctx is done or Stop is
+// called.
+func NewCycleTicker(ctx context.Context, opts TickerOptions) *CycleTicker {
+	ctx, cancel := context.WithCancel(ctx)
+	c := make(chan AIRAC, 1)
+
+	clock := clockOrSystem(opts.Clock)
+	current := opts.current(clock)
+
+	go opts.run(ctx, c, clock, current)
+
+	return &CycleTicker{C: c, Current: current, cancel: cancel}
+}
+
+// Stop turns off the ticker.
+func (t *CycleTicker) Stop() { t.cancel() }
+
+func (o TickerOptions) run(ctx context.Context, c chan<- AIRAC, clock Clock, last AIRAC) {
+	defer close(c)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-clock.After(o.sleep(clock, last+1)):
+		}
+
+		cur := o.current(clock)
+		if cur <= last {
+			// no boundary passed, or the clock has been set back
+			last = cur
+			continue
+		}
+		last = cur
+
+		select {
+		case c <- cur:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// WaitUntilEffective blocks until the AIRAC cycle a is effective, i.e. until
+// the effective date of a shifted by Offset has passed, or until ctx is done.
+// It returns ctx.Err() in the latter case.
+func (o TickerOptions) WaitUntilEffective(ctx context.Context, a AIRAC) error {
+	clock := clockOrSystem(o.Clock)
+
+	for o.current(clock) < a {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock.After(o.sleep(clock, a)):
+		}
+	}
+
+	return nil
+}
+
+// WaitUntilEffective blocks until the AIRAC cycle a is effective or until ctx
+// is done, using the system clock. It returns ctx.Err() in the latter case.
+func WaitUntilEffective(ctx context.Context, a AIRAC) error {
+	return TickerOptions{}.WaitUntilEffective(ctx, a)
+}
+
+// current returns the latest cycle whose shifted boundary has passed.
+func (o TickerOptions) current(clock Clock) AIRAC {
+	return FromDate(clock.Now().Add(-o.Offset))
+}
+
+// sleep returns how long to wait for the shifted boundary of a.
+func (o TickerOptions) sleep(clock Clock, a AIRAC) time.Duration {
+	return NextWakeup(clock.Now(), o.Recheck, a.Effective().Add(o.Offset))
+}
+
+// NextWakeup returns how long to wait from now until the earliest of the
+// boundaries that lie after now, capped by recheck. Timers do not account for
+// system sleep or for the wall clock being set, so a loop waiting for a
+// boundary should consult the clock again at least every recheck. A recheck of
+// zero or less means DefaultRecheck.
+func NextWakeup(now time.Time, recheck time.Duration, boundaries ...time.Time) time.Duration {
+	d := recheck
+	if d <= 0 {
+		d = DefaultRecheck
+	}
+
+	for _, b := range boundaries {
+		if until := b.Sub(now); until > 0 && until < d {
+			d = until
+		}
+	}
+
+	return d
+}
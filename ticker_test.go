@@ -0,0 +1,209 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jwkohnen/airac/internal/fakeclock"
+)
+
+func TestCycleTicker(t *testing.T) {
+	t.Parallel()
+
+	testt := []struct {
+		name    string
+		start   time.Time
+		offset  time.Duration
+		advance time.Duration
+		want    string
+	}{
+		{"boundary", time.Date(2021, time.January, 27, 12, 0, 0, 0, time.UTC), 0, 12 * time.Hour, "2101"},
+		{"offset", time.Date(2021, time.January, 20, 12, 0, 0, 0, time.UTC), -7 * 24 * time.Hour, 12 * time.Hour, "2101"},
+	}
+
+	for _, tt := range testt {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := fakeclock.New(tt.start)
+			ticker := NewCycleTicker(context.Background(), TickerOptions{Clock: clock, Offset: tt.offset})
+			defer ticker.Stop()
+
+			clock.BlockUntil(1)
+			clock.Advance(tt.advance - time.Nanosecond)
+			clock.BlockUntil(1)
+			select {
+			case got := <-ticker.C:
+				t.Fatalf("ticker fired early with %s", got)
+			default:
+			}
+
+			clock.Advance(time.Nanosecond)
+			if got := <-ticker.C; got.String() != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCycleTickerCurrent(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Date(2021, time.January, 27, 23, 59, 0, 0, time.UTC))
+	ticker := NewCycleTicker(context.Background(), TickerOptions{Clock: clock})
+	defer ticker.Stop()
+
+	if got, want := ticker.Current, FromStringMust("2014"); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+
+	// the boundary passes before the ticker first consults the clock
+	clock.Set(time.Date(2021, time.January, 28, 0, 1, 0, 0, time.UTC))
+	clock.BlockUntil(1)
+	clock.Advance(DefaultRecheck)
+
+	if got, want := <-ticker.C, FromStringMust("2101"); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestCycleTickerMissedBoundaries(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Date(2021, time.January, 27, 12, 0, 0, 0, time.UTC))
+	ticker := NewCycleTicker(context.Background(), TickerOptions{Clock: clock})
+	defer ticker.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(60 * 24 * time.Hour)
+
+	if got, want := <-ticker.C, FromStringMust("2103"); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestCycleTickerClockJump(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2021, time.January, 27, 12, 0, 0, 0, time.UTC)
+	clock := fakeclock.New(start)
+	ticker := NewCycleTicker(context.Background(), TickerOptions{Clock: clock})
+	defer ticker.Stop()
+
+	// wake up from suspend: the wall clock moved, but timers did not
+	clock.BlockUntil(1)
+	clock.Set(start.Add(30 * 24 * time.Hour))
+	clock.Advance(DefaultRecheck)
+
+	if got, want := <-ticker.C, FromStringMust("2102"); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+
+	// the clock is set back; the ticker must not fire for the past
+	clock.BlockUntil(1)
+	clock.Set(start)
+	clock.Advance(DefaultRecheck)
+	clock.BlockUntil(1)
+	clock.Advance(12 * time.Hour)
+
+	if got, want := <-ticker.C, FromStringMust("2101"); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestCycleTickerStop(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Date(2021, time.January, 27, 12, 0, 0, 0, time.UTC))
+	ticker := NewCycleTicker(context.Background(), TickerOptions{Clock: clock})
+	ticker.Stop()
+
+	if a, ok := <-ticker.C; ok {
+		t.Errorf("want closed channel, got %s", a)
+	}
+}
+
+func TestWaitUntilEffective(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Date(2021, time.January, 27, 12, 0, 0, 0, time.UTC))
+	opts := TickerOptions{Clock: clock, Offset: time.Hour}
+
+	done := make(chan error)
+	go func() { done <- opts.WaitUntilEffective(context.Background(), FromStringMust("2101")) }()
+
+	clock.BlockUntil(1)
+	clock.Advance(12 * time.Hour)
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	if err := <-done; err != nil {
+		t.Errorf("want nil, got %v", err)
+	}
+
+	if err := opts.WaitUntilEffective(context.Background(), FromStringMust("2014")); err != nil {
+		t.Errorf("cycle already effective: want nil, got %v", err)
+	}
+}
+
+func TestWaitUntilEffectiveCanceled(t *testing.T) {
+	t.Parallel()
+
+	clock := fakeclock.New(time.Date(2021, time.January, 27, 12, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() { done <- TickerOptions{Clock: clock}.WaitUntilEffective(ctx, FromStringMust("2102")) }()
+
+	clock.BlockUntil(1)
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestNextWakeup(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, time.January, 27, 12, 0, 0, 0, time.UTC)
+
+	testt := []struct {
+		name       string
+		recheck    time.Duration
+		boundaries []time.Time
+		want       time.Duration
+	}{
+		{"none", 0, nil, DefaultRecheck},
+		{"recheck", time.Hour, nil, time.Hour},
+		{"ahead", time.Hour, []time.Time{now.Add(time.Minute)}, time.Minute},
+		{"beyond recheck", time.Hour, []time.Time{now.Add(2 * time.Hour)}, time.Hour},
+		{"passed", time.Hour, []time.Time{now, now.Add(-time.Minute)}, time.Hour},
+		{"earliest", time.Hour, []time.Time{now.Add(-time.Minute), now.Add(3 * time.Minute), now.Add(2 * time.Minute)}, 2 * time.Minute},
+	}
+
+	for _, tt := range testt {
+		if got := NextWakeup(now, tt.recheck, tt.boundaries...); got != tt.want {
+			t.Errorf("%s: want %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Timeline is a value that changes with AIRAC cycles, e.g. reference data
+// that is "effective from cycle 2101 until superseded". Each version set for a
+// cycle is in force from that cycle until the cycle of the next version.
+// Versions are kept in chronological order like ByChrono, and lookups are by
+// binary search.
+//
+// The zero value is an empty timeline ready to use. A Timeline must not be
+// modified concurrently with other calls.
+type Timeline[T any] struct {
+	versions []Version[T]
+}
+
+// Version is a value of a Timeline that becomes effective with Cycle.
+type Version[T any] struct {
+	Cycle AIRAC
+	Value T
+}
+
+// Change is a version of a Timeline together with the value it supersedes.
+type Change[T any] struct {
+	Cycle AIRAC
+	Old   T
+	New   T
+
+	// HasOld is false if there is no earlier version, i.e. Old is the zero
+	// value.
+	HasOld bool
+}
+
+// Len returns the number of versions.
+func (tl *Timeline[T]) Len() int { return len(tl.versions) }
+
+// Set sets the value that becomes effective with cycle a, replacing an
+// earlier Set for the same cycle.
+func (tl *Timeline[T]) Set(a AIRAC, v T) {
+	i := tl.search(a)
+	if tl.has(a) {
+		tl.versions[i].Value = v
+		return
+	}
+	tl.versions = slices.Insert(tl.versions, i, Version[T]{Cycle: a, Value: v})
+}
+
+// Delete removes the version set for cycle a, so that the previous version
+// stays in force. It reports whether there was such a version.
+func (tl *Timeline[T]) Delete(a AIRAC) bool {
+	if !tl.has(a) {
+		return false
+	}
+	i := tl.search(a)
+	tl.versions = slices.Delete(tl.versions, i, i+1)
+	return true
+}
+
+// AtCycle returns the version in force during cycle a, i.e. the latest one
+// set for a or an earlier cycle. ok is false if there is none.
+func (tl *Timeline[T]) AtCycle(a AIRAC) (v Version[T], ok bool) {
+	i := sort.Search(len(tl.versions), func(i int) bool { return tl.versions[i].Cycle > a })
+	if i == 0 {
+		return v, false
+	}
+	return tl.versions[i-1], true
+}
+
+// At returns the value in force at t. ok is false if there is none.
+func (tl *Timeline[T]) At(t time.Time) (v T, ok bool) {
+	version, ok := tl.AtCycle(FromDate(t))
+	return version.Value, ok
+}
+
+// AtPrecise is like At, but cycles change at 00:01 UTC, see FromDatePrecise.
+func (tl *Timeline[T]) AtPrecise(t time.Time) (v T, ok bool) {
+	version, ok := tl.AtCycle(FromDatePrecise(t))
+	return version.Value, ok
+}
+
+// Versions returns all versions in chronological order.
+func (tl *Timeline[T]) Versions() []Version[T] { return slices.Clone(tl.versions) }
+
+// History returns an iterator over the cycles and values of all versions in
+// chronological order.
+func (tl *Timeline[T]) History() iter.Seq2[AIRAC, T] {
+	return func(yield func(AIRAC, T) bool) {
+		for _, v := range tl.versions {
+			if !yield(v.Cycle, v.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Diff returns the changes that take effect after cycle from up to and
+// including cycle to, in chronological order. The Old value of the first
+// change is the value in force during from. Diff returns none if to is not
+// after from.
+func (tl *Timeline[T]) Diff(from, to AIRAC) []Change[T] {
+	if to <= from {
+		return nil
+	}
+
+	var changes []Change[T]
+
+	prev, hasPrev := tl.AtCycle(from)
+	for i := tl.search(from + 1); i < len(tl.versions) && tl.versions[i].Cycle <= to; i++ {
+		v := tl.versions[i]
+		changes = append(changes, Change[T]{Cycle: v.Cycle, Old: prev.Value, New: v.Value, HasOld: hasPrev})
+		prev, hasPrev = v, true
+	}
+
+	return changes
+}
+
+// search returns the index of the first version of cycle a or later.
+func (tl *Timeline[T]) search(a AIRAC) int {
+	return sort.Search(len(tl.versions), func(i int) bool { return tl.versions[i].Cycle >= a })
+}
+
+// has reports whether there is a version set for cycle a.
+func (tl *Timeline[T]) has(a AIRAC) bool {
+	i := tl.search(a)
+	return i < len(tl.versions) && tl.versions[i].Cycle == a
+}
+
+type versionJSON struct {
+	Ident     string          `json:"ident"`
+	Effective string          `json:"effective,omitempty"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// MarshalJSON encodes the versions in chronological order like:
+//
+//	[
+//	  {"ident": "2101", "effective": "2021-01-28", "value": ...},
+//	  {"ident": "2107", "effective": "2021-07-15", "value": ...}
+//	]
+//
+// The effective date is informational only.
+func (tl Timeline[T]) MarshalJSON() ([]byte, error) {
+	versions := make([]versionJSON, 0, len(tl.versions))
+	for _, v := range tl.versions {
+		value, err := json.Marshal(v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("cycle %s: %w", v.Cycle, err)
+		}
+		versions = append(versions, versionJSON{
+			Ident:     v.Cycle.String(),
+			Effective: v.Cycle.Effective().Format(format),
+			Value:     value,
+		})
+	}
+	return json.Marshal(versions)
+}
+
+// UnmarshalJSON decodes versions in the format of MarshalJSON and replaces
+// the timeline with them. The versions may be in any order, but each cycle
+// may occur only once. An effective date, if present, must match the cycle.
+func (tl *Timeline[T]) UnmarshalJSON(b []byte) error {
+	var versions []versionJSON
+	if err := json.Unmarshal(b, &versions); err != nil {
+		return fmt.Errorf("illegal timeline: %w", err)
+	}
+
+	var res Timeline[T]
+	for _, vj := range versions {
+		a, err := FromString(vj.Ident)
+		if err != nil {
+			return fmt.Errorf("illegal timeline: %w", err)
+		}
+		if vj.Effective != "" && vj.Effective != a.Effective().Format(format) {
+			return fmt.Errorf("illegal timeline: cycle %s is effective %s, not %s", a, a.Effective().Format(format), vj.Effective)
+		}
+		if res.has(a) {
+			return fmt.Errorf("illegal timeline: duplicate cycle %s", a)
+		}
+
+		var v T
+		if err := json.Unmarshal(vj.Value, &v); err != nil {
+			return fmt.Errorf("illegal timeline: cycle %s: %w", a, err)
+		}
+		res.Set(a, v)
+	}
+
+	*tl = res
+	return nil
+}
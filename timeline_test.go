@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2020 Johannes Kohnen <jwkohnen-github@ko-sys.com>
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package airac
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func timeline(t *testing.T, versions ...string) *Timeline[string] {
+	t.Helper()
+
+	var tl Timeline[string]
+	for _, v := range versions {
+		tl.Set(ident(t, v[:4]), v[5:])
+	}
+	return &tl
+}
+
+func TestTimelineAt(t *testing.T) {
+	t.Parallel()
+
+	tl := timeline(t, "2107 B", "2101 A", "2113 C")
+
+	testt := []struct {
+		cycle string
+		want  string
+	}{
+		{"2014", ""},
+		{"2101", "A"},
+		{"2106", "A"},
+		{"2107", "B"},
+		{"2112", "B"},
+		{"2113", "C"},
+		{"6313", "C"},
+	}
+
+	for _, tt := range testt {
+		v, ok := tl.AtCycle(ident(t, tt.cycle))
+		if ok != (tt.want != "") || v.Value != tt.want {
+			t.Errorf("%s: want %q, got %q, %v", tt.cycle, tt.want, v.Value, ok)
+		}
+	}
+
+	if v, ok := tl.AtCycle(65535); !ok || v.Value != "C" {
+		t.Errorf("65535: want C, got %q, %v", v.Value, ok)
+	}
+
+	if v, ok := tl.At(date("2021-07-15")); !ok || v != "B" {
+		t.Errorf("2021-07-15: want B, got %q, %v", v, ok)
+	}
+	if v, ok := tl.AtPrecise(date("2021-07-15")); !ok || v != "A" {
+		t.Errorf("2021-07-15 00:00 precise: want A, got %q, %v", v, ok)
+	}
+}
+
+func TestTimelineSetDelete(t *testing.T) {
+	t.Parallel()
+
+	tl := timeline(t, "2101 A", "2107 B")
+	tl.Set(ident(t, "2107"), "B2")
+	tl.Set(ident(t, "2104"), "A2")
+
+	var got []string
+	for a, v := range tl.History() {
+		got = append(got, a.String()+" "+v)
+	}
+	if want := "2101 A, 2104 A2, 2107 B2"; strings.Join(got, ", ") != want {
+		t.Errorf("want %q, got %q", want, strings.Join(got, ", "))
+	}
+
+	if !tl.Delete(ident(t, "2104")) || tl.Delete(ident(t, "2105")) || tl.Len() != 2 {
+		t.Errorf("want 2104 deleted once, got %d versions", tl.Len())
+	}
+	if v, _ := tl.AtCycle(ident(t, "2105")); v.Value != "A" || v.Cycle != ident(t, "2101") {
+		t.Errorf("want 2101 A in force after delete, got %s %q", v.Cycle, v.Value)
+	}
+}
+
+func TestTimelineDiff(t *testing.T) {
+	t.Parallel()
+
+	tl := timeline(t, "2101 A", "2107 B", "2113 C")
+
+	testt := []struct {
+		from, to string
+		want     string
+	}{
+		{"2014", "2101", "2101 -> A"},
+		{"2101", "2106", ""},
+		{"2101", "2107", "2107 A -> B"},
+		{"2103", "2201", "2107 A -> B, 2113 B -> C"},
+		{"2013", "2201", "2101 -> A, 2107 A -> B, 2113 B -> C"},
+		{"2107", "2101", ""},
+	}
+
+	for _, tt := range testt {
+		var got []string
+		for _, c := range tl.Diff(ident(t, tt.from), ident(t, tt.to)) {
+			if c.HasOld {
+				got = append(got, fmt.Sprintf("%s %s -> %s", c.Cycle, c.Old, c.New))
+			} else {
+				got = append(got, fmt.Sprintf("%s -> %s", c.Cycle, c.New))
+			}
+		}
+		if strings.Join(got, ", ") != tt.want {
+			t.Errorf("%s..%s: want %q, got %q", tt.from, tt.to, tt.want, strings.Join(got, ", "))
+		}
+	}
+}
+
+func TestTimelineJSON(t *testing.T) {
+	t.Parallel()
+
+	type limits struct {
+		MaxAlt int `json:"max_alt"`
+	}
+
+	var tl Timeline[limits]
+	tl.Set(ident(t, "2107"), limits{24500})
+	tl.Set(ident(t, "2101"), limits{19500})
+
+	b, err := json.Marshal(&tl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"ident":"2101","effective":"2021-01-28","value":{"max_alt":19500}},` +
+		`{"ident":"2107","effective":"2021-07-15","value":{"max_alt":24500}}]`
+	if string(b) != want {
+		t.Errorf("want %s, got %s", want, b)
+	}
+
+	var back Timeline[limits]
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := back.AtCycle(ident(t, "2110")); !ok || v.Value.MaxAlt != 24500 || back.Len() != 2 {
+		t.Errorf("want 24500 of 2 versions, got %v, %d", v.Value, back.Len())
+	}
+
+	// by value, as a struct field
+	type config struct{ Limits Timeline[limits] }
+	b, err = json.Marshal(config{tl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Limits":` + want + `}`; string(b) != want {
+		t.Errorf("want %s, got %s", want, b)
+	}
+	if b, err := json.Marshal(tl); err != nil || string(b) != want {
+		t.Errorf("want %s, got %s, %v", want, b, err)
+	}
+
+	var cfg config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Limits.Versions(); len(got) != 2 || got[0].Value.MaxAlt != 19500 || got[1].Cycle != ident(t, "2107") {
+		t.Errorf("want both versions to round-trip, got %v", got)
+	}
+
+	for _, in := range []string{
+		`{}`,
+		`[{"ident":"2199","value":{}}]`,
+		`[{"ident":"2101","effective":"2021-01-29","value":{}}]`,
+		`[{"ident":"2101","value":{}},{"ident":"2101","value":{}}]`,
+		`[{"ident":"2101","value":"x"}]`,
+	} {
+		if err := json.Unmarshal([]byte(in), &back); err == nil {
+			t.Errorf("%s: want error, got nil", in)
+		}
+	}
+}
+
+func ExampleTimeline() {
+	var frequency Timeline[string]
+	frequency.Set(FromStringMust("2101"), "118.500")
+	frequency.Set(FromStringMust("2107"), "118.525")
+
+	v, _ := frequency.AtCycle(FromStringMust("2104"))
+	fmt.Println(v.Value)
+
+	for _, c := range frequency.Diff(FromStringMust("2104"), FromStringMust("2110")) {
+		fmt.Printf("%s: %s -> %s\n", c.Cycle, c.Old, c.New)
+	}
+	// Output:
+	// 118.500
+	// 2107: 118.500 -> 118.525
+}